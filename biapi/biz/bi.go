@@ -5,6 +5,8 @@ package biz
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/qq1060656096/bizutil/qsql"
 	"gorm.io/gorm"
@@ -21,6 +23,8 @@ const (
 	OpTypeList   = 401
 	OpTypeDetail = 402
 	OpTypeCount  = 403
+	// OpTypeListPaged 分页列表，与 OpTypeList 相同但总是同时查询总记录数
+	OpTypeListPaged = 404
 )
 
 // 预定义的环境常量。
@@ -37,8 +41,57 @@ var (
 	ErrDSLParseFailed       = errors.New("biz: dsl parse failed")
 	ErrDSLExecuteFailed     = errors.New("biz: dsl execute failed")
 	ErrUnsupportedOpType    = errors.New("biz: unsupported op type")
+	ErrDSLHasErrors         = errors.New("biz: dsl has errors")
+	ErrNilDB                = errors.New("biz: nil db")
+	ErrInvalidVars          = errors.New("biz: invalid vars")
+	ErrDSLValidationFailed  = errors.New("biz: dsl validation failed")
+	ErrNilBuildResult       = errors.New("biz: nil build result")
 )
 
+// StmtError 表示 DSL 执行后 SQLStmt.Errors 非空（如缺少必填参数）时返回的错误。
+// 仅在严格模式下返回，可通过 errors.Is(err, ErrDSLHasErrors) 判断。
+type StmtError struct {
+	TdId   int64    // 模板数据 ID
+	Errors []string // SQLStmt 记录的错误列表
+}
+
+// Error 实现 error 接口。
+func (e *StmtError) Error() string {
+	return ErrDSLHasErrors.Error() + ": " + strings.Join(e.Errors, "; ")
+}
+
+// Unwrap 返回 ErrDSLHasErrors，支持 errors.Is 判断。
+func (e *StmtError) Unwrap() error {
+	return ErrDSLHasErrors
+}
+
+// ValidationError 表示 DSL 校验器（vRequired、vStr 等）校验失败时返回的错误。
+// 仅在严格校验模式下返回，可通过 errors.Is(err, ErrDSLValidationFailed) 判断，
+// 也可通过 errors.As 取出单个 *qsql.ValidatorError。
+type ValidationError struct {
+	TdId   int64                  // 模板数据 ID
+	Errors []*qsql.ValidatorError // 校验错误列表
+}
+
+// Error 实现 error 接口。
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, ve := range e.Errors {
+		msgs = append(msgs, ve.Error())
+	}
+	return ErrDSLValidationFailed.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap 返回 ErrDSLValidationFailed 及各校验错误，支持 errors.Is / errors.As 判断。
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	errs = append(errs, ErrDSLValidationFailed)
+	for _, ve := range e.Errors {
+		errs = append(errs, ve)
+	}
+	return errs
+}
+
 // ExecuteRequest 表示 BI 模板执行请求。
 type ExecuteRequest struct {
 	PlatformId int64  `json:"platform_id"` // 平台 ID
@@ -50,12 +103,13 @@ type ExecuteRequest struct {
 	Users      any    `json:"users"`       // 用户相关信息
 	Page       int    `json:"page"`        // 页码，从 1 开始
 	PageSize   int    `json:"page_size"`   // 每页数量
+	WithCount  bool   `json:"with_count"`  // list 操作是否同时查询总记录数（OpTypeListPaged 总是查询）
 }
 
 // ExecuteResult 表示 BI 模板执行结果。
 type ExecuteResult struct {
 	Data             any                    `json:"data"`             // 查询结果列表
-	Count            int64                  `json:"count"`            // 总记录数（list/count）
+	Count            int64                  `json:"count"`            // 总记录数（list 开启 WithCount、list paged、count）
 	ValidatorsErrors []*qsql.ValidatorError `json:"validator_errors"` // DSL 校验错误
 	Errors           []error                `json:"errors"`
 	RowsAffected     int64                  `json:"rows_affected"`          // 受影响行数
@@ -63,10 +117,13 @@ type ExecuteResult struct {
 	BuildResult      *BuildResult           `json:"build_result,omitempty"` // 构建结果（调试用）
 }
 
+// BuildResult 表示 DSL 构建结果。
 type BuildResult struct {
-	TdId    int64
-	OpType  int
-	SQLStmt *qsql.SQLStmt
+	TdId          int64
+	OpType        int
+	SQLStmt       *qsql.SQLStmt
+	ParseDuration time.Duration // 模板解析耗时
+	ExecDuration  time.Duration // 模板执行（生成 SQL）耗时
 }
 
 // TemplateUsecase 定义 BI 模板业务逻辑接口。
@@ -76,6 +133,9 @@ type BiRepo interface {
 
 	// Build 仅解析 DSL 并生成 SQL，不执行查询。
 	Build(ctx context.Context, tplDb *gorm.DB, req *ExecuteRequest) (*BuildResult, error)
+
+	// ExecuteBuild 执行 Build 返回的构建结果，适用于先 Build 检查校验错误再执行的场景，避免重复构建模板。
+	ExecuteBuild(ctx context.Context, execDB *gorm.DB, req *ExecuteRequest, buildResult *BuildResult) (*ExecuteResult, error)
 }
 
 type BiUsecase struct {
@@ -93,6 +153,11 @@ func (u *BiUsecase) Execute(ctx context.Context, tplDb, execDB *gorm.DB, req *Ex
 	return u.repo.Execute(ctx, tplDb, execDB, req)
 }
 
+// ExecuteBuild 执行 Build 返回的构建结果，避免重复构建模板。
+func (u *BiUsecase) ExecuteBuild(ctx context.Context, execDB *gorm.DB, req *ExecuteRequest, buildResult *BuildResult) (*ExecuteResult, error) {
+	return u.repo.ExecuteBuild(ctx, execDB, req, buildResult)
+}
+
 // Build 仅解析 DSL 并生成 SQL，不执行查询。
 func (u *BiUsecase) Build(ctx context.Context, tplDb *gorm.DB, req *ExecuteRequest) (*BuildResult, error) {
 	return u.repo.Build(ctx, tplDb, req)
@@ -2,6 +2,11 @@ package data
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/qq1060656096/bizutil/qsql"
 	"github.com/qq1060656096/drugo-provider/biapi/biz"
@@ -16,43 +21,220 @@ const Name = "bi"
 var _ biz.BiRepo = (*BiRepo)(nil)
 
 type BiRepo struct {
-	tplRepo *templateRepo
-	name    string
+	tplRepo          *templateRepo
+	name             string
+	strictErrors     bool
+	strictValidation bool
+	txWrites         bool
+	tplCache         *templateCache
+	metricsHook      MetricsHook
+}
+
+// Metrics 单次模板执行的耗时与结果统计。
+type Metrics struct {
+	Code            string        // 模板业务编码
+	OpType          int           // 操作类型，构建失败时为 0
+	ParseDuration   time.Duration // 模板解析耗时，命中缓存时为 0
+	BuildDuration   time.Duration // 模板执行（生成 SQL）耗时
+	ExecuteDuration time.Duration // SQL 执行耗时（含 COUNT 查询）
+	RowsAffected    int64         // 受影响行数
+	Err             error         // 执行错误，成功时为 nil
+}
+
+// MetricsHook 模板执行完成（包括失败）后调用的指标回调。
+type MetricsHook func(ctx context.Context, m *Metrics)
+
+// Option 定义 BiRepo 的配置选项函数类型。
+type Option func(*BiRepo)
+
+// WithStrictValidation 设置是否启用严格校验模式。
+// 启用后 Build 在 SQLStmt.ValidatorsErrors 非空时返回 *biz.ValidationError，
+// 且返回的构建结果中 SQL 与 Args 为空，SQL 不会被执行；默认关闭。
+func WithStrictValidation(strict bool) Option {
+	return func(b *BiRepo) {
+		b.strictValidation = strict
+	}
+}
+
+// WithTemplateCacheSize 设置已解析模板的 LRU 缓存容量，默认 256，size <= 0 表示关闭缓存。
+//
+// 缓存以模板数据的 checksum 判断内容是否变化，checksum 变化时重新解析；
+// checksum 为空的模板数据不缓存。
+func WithTemplateCacheSize(size int) Option {
+	return func(b *BiRepo) {
+		if size <= 0 {
+			b.tplCache = nil
+			return
+		}
+		b.tplCache = newTemplateCache(size)
+	}
+}
+
+// WithTxWrites 设置写操作（add/update/del）是否在事务中执行。
+// 启用后模板生成的 SQL（包括多条语句）在同一事务中执行，任一语句失败时整体回滚；默认关闭。
+func WithTxWrites(enabled bool) Option {
+	return func(b *BiRepo) {
+		b.txWrites = enabled
+	}
+}
+
+// WithMetricsHook 设置指标回调，每次 Execute 完成后调用，可用于上报耗时、受影响行数等指标；
+// 默认不设置，原有的 zap 日志不受影响。
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(b *BiRepo) {
+		b.metricsHook = hook
+	}
+}
+
+// WithStrictErrors 设置是否启用严格模式。
+// 启用后 Build 在 SQLStmt.Errors 非空时返回 *biz.StmtError，SQL 不会被执行；
+// 默认关闭（宽松模式），仅记录错误并继续执行。
+func WithStrictErrors(strict bool) Option {
+	return func(b *BiRepo) {
+		b.strictErrors = strict
+	}
 }
 
 func (b *BiRepo) Execute(ctx context.Context, tplDb, execDB *gorm.DB, req *biz.ExecuteRequest) (*biz.ExecuteResult, error) {
-	buildResult, err := b.Build(ctx, tplDb, req)
-	appLogger := drugo.App().Logger().MustGet(Name)
+	return b.executeMaps(ctx, tplDb, execDB, req, nil)
+}
+
+// ExecuteBuild 执行 Build 返回的构建结果，不再查询和渲染模板，结果与 Execute 相同。
+// 调用方应先检查 Build 返回的错误及校验错误，buildResult 为 nil 时返回 biz.ErrNilBuildResult。
+func (b *BiRepo) ExecuteBuild(ctx context.Context, execDB *gorm.DB, req *biz.ExecuteRequest, buildResult *biz.BuildResult) (*biz.ExecuteResult, error) {
+	if buildResult == nil || buildResult.SQLStmt == nil {
+		return nil, biz.ErrNilBuildResult
+	}
+	return b.executeMaps(ctx, nil, execDB, req, buildResult)
+}
+
+// executeMaps 执行模板，list 结果扫描为 []map[string]any，detail 结果扫描为 map[string]any。
+func (b *BiRepo) executeMaps(ctx context.Context, tplDb, execDB *gorm.DB, req *biz.ExecuteRequest, buildResult *biz.BuildResult) (*biz.ExecuteResult, error) {
+	var list []map[string]any
+	var detail map[string]any
+	executeResult, err := b.execute(ctx, tplDb, execDB, req, buildResult, &list, &detail)
 	if err != nil {
-		appLogger.Error("BiRepo.Build", zap.Error(err),
-			zap.Any("req", req),
-			zap.Any("buildResult", buildResult),
-		)
 		return nil, err
 	}
+	switch executeResult.OpType {
+	case biz.OpTypeList, biz.OpTypeListPaged:
+		if list == nil {
+			list = []map[string]any{}
+		}
+		executeResult.Data = list
+	case biz.OpTypeDetail:
+		executeResult.Data = detail
+	}
+	return executeResult, nil
+}
+
+// ExecuteInto 与 BiRepo.Execute 相同，但 list / detail 查询结果通过 gorm 扫描为 []T，
+// 避免调用方逐个字段断言类型；T 通常为按 gorm 列名映射的结构体。
+//
+// list 类操作返回全部行（无数据时为空切片），detail 操作最多返回一行，
+// 其余操作返回 nil；ExecuteResult 中的 Data 与返回的 []T 相同，其余元数据与 Execute 一致。
+// 动态字段场景仍使用 Execute 返回的 map 结果。
+//
+// 示例：
+//
+//	type User struct {
+//		Name string `gorm:"column:name"`
+//		Age  int    `gorm:"column:age"`
+//	}
+//	users, result, err := data.ExecuteInto[User](ctx, repo, tplDb, execDB, req)
+func ExecuteInto[T any](ctx context.Context, repo *BiRepo, tplDb, execDB *gorm.DB, req *biz.ExecuteRequest) ([]T, *biz.ExecuteResult, error) {
+	var list []T
+	// detail 也扫描为切片，便于区分未查询到数据
+	executeResult, err := repo.execute(ctx, tplDb, execDB, req, nil, &list, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch executeResult.OpType {
+	case biz.OpTypeList, biz.OpTypeListPaged, biz.OpTypeDetail:
+		if list == nil {
+			list = []T{}
+		}
+		if executeResult.OpType == biz.OpTypeDetail && len(list) > 1 {
+			list = list[:1]
+		}
+		executeResult.Data = list
+	default:
+		list = nil
+	}
+	return list, executeResult, nil
+}
+
+// execute 构建并执行模板，list 类操作的结果扫描到 listDest（切片指针），
+// detail 操作的结果扫描到 detailDest，由调用方根据操作类型设置 ExecuteResult.Data。
+// buildResult 不为 nil 时直接执行，不再构建模板。
+func (b *BiRepo) execute(ctx context.Context, tplDb, execDB *gorm.DB, req *biz.ExecuteRequest, buildResult *biz.BuildResult, listDest, detailDest any) (executeResult *biz.ExecuteResult, err error) {
+	m := &Metrics{Code: req.Code}
+	var execStart time.Time
+	if b.metricsHook != nil {
+		defer func() {
+			if !execStart.IsZero() {
+				m.ExecuteDuration = time.Since(execStart)
+			}
+			if executeResult != nil {
+				m.RowsAffected = executeResult.RowsAffected
+			}
+			m.Err = err
+			b.metricsHook(ctx, m)
+		}()
+	}
+	if execDB == nil {
+		return nil, fmt.Errorf("%w: execDB", biz.ErrNilDB)
+	}
+	appLogger := drugo.App().Logger().MustGet(Name)
+	if buildResult == nil {
+		buildResult, err = b.Build(ctx, tplDb, req)
+		if err != nil {
+			appLogger.Error("BiRepo.Build", zap.Error(err),
+				zap.Any("req", req),
+				zap.Any("buildResult", buildResult),
+			)
+			return nil, err
+		}
+	}
+	// 构建完成后再次检查，请求已超时或取消时不再执行 SQL
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.OpType = buildResult.OpType
+	m.ParseDuration = buildResult.ParseDuration
+	m.BuildDuration = buildResult.ExecDuration
+	execStart = time.Now()
+
+	// db 绑定 ctx，执行和扫描过程中 ctx 取消时由驱动中断查询
 	db := execDB.WithContext(ctx)
-	var returnData any
 	var count int64
 	var rowsAffected int64
 	sql := buildResult.SQLStmt.SQL
 	args := buildResult.SQLStmt.Args
 	switch buildResult.OpType {
-	case biz.OpTypeList:
-		var data []map[string]any
-		err := db.Raw(sql, args...).Scan(&data).Error
+	case biz.OpTypeList, biz.OpTypeListPaged:
+		err := db.Raw(sql, args...).Scan(listDest).Error
 		if err != nil {
 			return nil, err
 		}
-		returnData = data
-		rowsAffected = int64(len(data))
+		rowsAffected = int64(reflect.ValueOf(listDest).Elem().Len())
+		if req.WithCount || buildResult.OpType == biz.OpTypeListPaged {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			// 基于列表 SQL 派生 COUNT 查询，复用相同的绑定参数
+			countSQL, countArgs := buildCountSQL(sql, args)
+			err := db.Raw(countSQL, countArgs...).Scan(&count).Error
+			if err != nil {
+				return nil, err
+			}
+		}
 
 	case biz.OpTypeDetail:
-		var detail map[string]any
-		err := db.Raw(sql, args...).Scan(&detail).Error
+		err := db.Raw(sql, args...).Scan(detailDest).Error
 		if err != nil {
 			return nil, err
 		}
-		returnData = detail
 		rowsAffected = 1
 	case biz.OpTypeCount:
 		err := db.Raw(sql, args...).Scan(&count).Error
@@ -61,17 +243,31 @@ func (b *BiRepo) Execute(ctx context.Context, tplDb, execDB *gorm.DB, req *biz.E
 		}
 		rowsAffected = count
 	case biz.OpTypeAdd, biz.OpTypeUpdate, biz.OpTypeDel:
-		result := db.Exec(sql, args...)
-		if result.Error != nil {
-			return nil, result.Error
+		if !b.txWrites {
+			result := db.Exec(sql, args...)
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			rowsAffected = result.RowsAffected
+			break
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Exec(sql, args...)
+			if result.Error != nil {
+				return result.Error
+			}
+			rowsAffected = result.RowsAffected
+			return nil
+		})
+		if err != nil {
+			appLogger.Error("BiRepo.Execute transaction rollback", zap.Error(err), zap.Int64("tdId", buildResult.TdId))
+			return nil, err
 		}
-		rowsAffected = result.RowsAffected
 	}
 
-	executeResult := &biz.ExecuteResult{
+	executeResult = &biz.ExecuteResult{
 		OpType:           buildResult.OpType,
 		RowsAffected:     rowsAffected,
-		Data:             returnData,
 		Count:            count,
 		ValidatorsErrors: buildResult.SQLStmt.ValidatorsErrors,
 		BuildResult:      buildResult,
@@ -86,6 +282,12 @@ func (b *BiRepo) Execute(ctx context.Context, tplDb, execDB *gorm.DB, req *biz.E
 }
 
 func (b *BiRepo) Build(ctx context.Context, tplDb *gorm.DB, req *biz.ExecuteRequest) (*biz.BuildResult, error) {
+	if tplDb == nil {
+		return nil, fmt.Errorf("%w: tplDb", biz.ErrNilDB)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	tpl, err := b.tplRepo.FindTpl(ctx, tplDb, req.PlatformId, req.Code)
 	appLogger := drugo.App().Logger().MustGet(Name)
 	if err != nil {
@@ -98,34 +300,136 @@ func (b *BiRepo) Build(ctx context.Context, tplDb *gorm.DB, req *biz.ExecuteRequ
 		appLogger.Error("BiRepo.Build template data not found", zap.Error(err), zap.Any("req", req))
 		return nil, err
 	}
-	content := tplData.Content
-	qe := qsql.NewEngine()
-	err = qe.Parse("sql", content)
+	parseStart := time.Now()
+	qe, err := b.parseTemplate(tplData)
+	parseDuration := time.Since(parseStart)
 	if err != nil {
 		appLogger.Error("BiRepo.Build template content parse", zap.Error(err), zap.Int64("tplId", tplId), zap.Any("req", req))
 		return nil, err
 	}
-	vars := qsql.NewValueVars()
-	vars.Params(req.Params)
-	vars.Sys(req.Sys)
-	vars.Users(req.Users)
+	vars, err := newBuildVars(req)
+	if err != nil {
+		appLogger.Error("BiRepo.Build template vars", zap.Error(err), zap.Int64("tplId", tplId), zap.Any("req", req))
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
+	execStart := time.Now()
 	stm, err := qe.ExecuteWithVars(vars)
+	execDuration := time.Since(execStart)
 	if err != nil {
 		appLogger.Error("BiRepo.Build template execution", zap.Error(err), zap.Int64("tplId", tplId), zap.Any("req", req), zap.Any("stm", stm))
 		return nil, err
 	}
 	rt := &biz.BuildResult{
-		TdId:    tplData.TdId,
-		OpType:  tplData.OpType,
-		SQLStmt: stm,
+		TdId:          tplData.TdId,
+		OpType:        tplData.OpType,
+		SQLStmt:       stm,
+		ParseDuration: parseDuration,
+		ExecDuration:  execDuration,
+	}
+	if stm.HasValidatorErrors() && b.strictValidation {
+		err = &biz.ValidationError{TdId: tplData.TdId, Errors: stm.ValidatorsErrors}
+		appLogger.Warn("BiRepo.Build template validation failed", zap.Error(err), zap.Int64("tplId", tplId), zap.Any("req", req))
+		// 校验失败时不输出 SQL，避免调用方误执行
+		rt.SQLStmt = &qsql.SQLStmt{RawSQL: stm.RawSQL, Errors: stm.Errors, ValidatorsErrors: stm.ValidatorsErrors}
+		return rt, err
+	}
+	if stm.HasErrors() {
+		if b.strictErrors {
+			err = &biz.StmtError{TdId: tplData.TdId, Errors: stm.Errors}
+			appLogger.Error("BiRepo.Build template has errors", zap.Error(err), zap.Int64("tplId", tplId), zap.Any("req", req), zap.Any("stm", stm))
+			// 返回构建结果便于调用方查看生成的 SQL
+			return rt, err
+		}
+		appLogger.Warn("BiRepo.Build template has errors", zap.Strings("errors", stm.Errors), zap.Int64("tplId", tplId), zap.Any("req", req))
 	}
 	return rt, nil
 }
 
-func NewBiRepo() *BiRepo {
-	return &BiRepo{
-		tplRepo: newTemplateRepo(),
-		name:    "biapi",
+// parseTemplate 解析模板内容，开启缓存时优先使用 checksum 一致的已解析模板。
+// 返回的 Engine 可能被并发请求共享，调用方只能执行，不能再次 Parse，见 templateCache。
+func (b *BiRepo) parseTemplate(tplData *TemplateData) (*qsql.Engine, error) {
+	cacheable := b.tplCache != nil && tplData.Checksum != ""
+	var key string
+	if cacheable {
+		key = strconv.FormatInt(tplData.PlatformId, 10) + ":" +
+			strconv.FormatInt(tplData.TemplateId, 10) + ":" +
+			strconv.FormatInt(tplData.TdId, 10)
+		if qe, ok := b.tplCache.get(key, tplData.Checksum); ok {
+			return qe, nil
+		}
+	}
+
+	qe := qsql.NewEngine()
+	if err := qe.Parse("sql", tplData.Content); err != nil {
+		return nil, err
+	}
+	if cacheable {
+		b.tplCache.add(key, tplData.Checksum, qe)
+	}
+	return qe, nil
+}
+
+// ValidateTemplate 对模板内容做一次试运行，用于在 CI 中不依赖数据库发现明显错误的模板，
+// 例如 expr 参数不足导致的 "expr: no values"。
+//
+// params 为试运行使用的示例参数（$.params），nil 表示空对象；
+// 模板解析或执行失败时返回 error，否则返回试运行收集到的 Errors 与 ValidatorsErrors。
+func ValidateTemplate(content string, params any) ([]string, []*qsql.ValidatorError, error) {
+	qe := qsql.NewEngine()
+	if err := qe.Parse("sql", content); err != nil {
+		return nil, nil, err
+	}
+	if params == nil {
+		params = map[string]any{}
+	}
+	vars, err := newBuildVars(&biz.ExecuteRequest{Params: params})
+	if err != nil {
+		return nil, nil, err
+	}
+	stm, err := qe.ExecuteWithVars(vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stm.Errors, stm.ValidatorsErrors, nil
+}
+
+// newBuildVars 将请求中的 Params / Sys / Users 序列化为模板变量。
+// 支持任意可 JSON 序列化的值（struct 按 json tag 生成字段名、map、slice 等），
+// 序列化失败时返回 biz.ErrInvalidVars，而不是静默丢弃该变量。
+func newBuildVars(req *biz.ExecuteRequest) (*qsql.JSONVars, error) {
+	vars := qsql.NewJSONVars()
+	for _, v := range []struct {
+		name  string
+		value any
+	}{
+		{"params", req.Params},
+		{"sys", req.Sys},
+		{"users", req.Users},
+	} {
+		b, err := json.Marshal(v.value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", biz.ErrInvalidVars, v.name, err)
+		}
+		if err := vars.SetRaw(v.name, string(b)); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", biz.ErrInvalidVars, v.name, err)
+		}
+	}
+	return vars, nil
+}
+
+// NewBiRepo 创建 BiRepo 实例。
+func NewBiRepo(opts ...Option) *BiRepo {
+	b := &BiRepo{
+		tplRepo:  newTemplateRepo(),
+		name:     "biapi",
+		tplCache: newTemplateCache(defaultTemplateCacheSize),
+	}
+	for _, opt := range opts {
+		opt(b)
 	}
+	return b
 }
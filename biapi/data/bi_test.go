@@ -0,0 +1,531 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/bizutil/qsql"
+	"github.com/qq1060656096/drugo-provider/biapi/biz"
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestApp 初始化测试用的 drugo 应用（BiRepo 通过 drugo.App() 获取日志）。
+func setupTestApp(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0o755))
+	drugo.SetApp(drugo.MustNewApp(drugo.WithRoot(root)))
+}
+
+// setupTestDB 创建内存 sqlite 数据库并初始化模板表。
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存库每个连接独立，限制为单连接保证数据可见
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	stmts := []string{
+		`CREATE TABLE bi_template (
+			template_id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform_id INTEGER NOT NULL,
+			company_id INTEGER NOT NULL DEFAULT 0,
+			code VARCHAR(64) NOT NULL,
+			name VARCHAR(128) NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 1,
+			created_at DATETIME,
+			updated_at DATETIME,
+			deleted_at DATETIME DEFAULT NULL
+		)`,
+		`CREATE TABLE bi_template_data (
+			td_id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform_id INTEGER NOT NULL,
+			template_id INTEGER NOT NULL,
+			company_id INTEGER NOT NULL,
+			env VARCHAR(8) NOT NULL DEFAULT 'test',
+			op_type INTEGER NOT NULL,
+			content TEXT NOT NULL,
+			checksum CHAR(32) NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 1,
+			created_at DATETIME,
+			updated_at DATETIME,
+			deleted_at DATETIME DEFAULT NULL
+		)`,
+		`CREATE TABLE users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name VARCHAR(64) NOT NULL,
+			age INTEGER NOT NULL DEFAULT 0
+		)`,
+		`INSERT INTO users (name, age) VALUES ('alice', 20), ('bob', 30), ('carol', 40)`,
+	}
+	for _, stmt := range stmts {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+// createTestTemplate 写入一个模板及其模板数据（platform_id=1, company_id=0, env=test）。
+func createTestTemplate(t *testing.T, db *gorm.DB, code string, opType int, content string) {
+	t.Helper()
+	tpl := &Template{PlatformId: 1, Code: code, Status: 1}
+	require.NoError(t, db.Create(tpl).Error)
+	tplData := &TemplateData{
+		PlatformId: 1,
+		TemplateId: tpl.TemplateId,
+		CompanyId:  0,
+		Env:        biz.EnvTest,
+		OpType:     opType,
+		Content:    content,
+		Status:     1,
+	}
+	require.NoError(t, db.Create(tplData).Error)
+}
+
+func newTestRequest(code string, params any) *biz.ExecuteRequest {
+	return &biz.ExecuteRequest{
+		PlatformId: 1,
+		CompanyId:  0,
+		Code:       code,
+		Env:        biz.EnvTest,
+		Params:     params,
+	}
+}
+
+func TestBiRepo_Build_MissingRequiredParams(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList,
+		`SELECT * FROM users WHERE {expr . "name" "=" "params.name"}`)
+
+	t.Run("宽松模式继续执行", func(t *testing.T) {
+		repo := NewBiRepo()
+		req := newTestRequest("user_list", map[string]any{})
+
+		buildResult, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+		assert.True(t, buildResult.SQLStmt.HasErrors())
+
+		result, err := repo.Execute(context.Background(), db, db, req)
+		require.NoError(t, err)
+		assert.Equal(t, biz.OpTypeList, result.OpType)
+	})
+
+	t.Run("严格模式返回类型化错误", func(t *testing.T) {
+		repo := NewBiRepo(WithStrictErrors(true))
+		req := newTestRequest("user_list", map[string]any{})
+
+		buildResult, err := repo.Build(context.Background(), db, req)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, biz.ErrDSLHasErrors))
+
+		var stmtErr *biz.StmtError
+		require.True(t, errors.As(err, &stmtErr))
+		assert.NotEmpty(t, stmtErr.Errors)
+		// 构建结果仍然返回，便于排查生成的 SQL
+		require.NotNil(t, buildResult)
+		assert.Equal(t, buildResult.TdId, stmtErr.TdId)
+		assert.NotEmpty(t, buildResult.SQLStmt.SQL)
+
+		result, err := repo.Execute(context.Background(), db, db, req)
+		assert.ErrorIs(t, err, biz.ErrDSLHasErrors)
+		assert.Nil(t, result)
+	})
+
+	t.Run("严格模式参数完整时正常执行", func(t *testing.T) {
+		repo := NewBiRepo(WithStrictErrors(true))
+		req := newTestRequest("user_list", map[string]any{"name": "alice"})
+
+		result, err := repo.Execute(context.Background(), db, db, req)
+		require.NoError(t, err)
+		data, ok := result.Data.([]map[string]any)
+		require.True(t, ok)
+		assert.Len(t, data, 1)
+		assert.Equal(t, "alice", data[0]["name"])
+	})
+}
+
+func TestBiRepo_Build_Durations(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList,
+		`SELECT * FROM users WHERE {expr . "age" ">=" "params.age"}`)
+
+	repo := NewBiRepo()
+	buildResult, err := repo.Build(context.Background(), db, newTestRequest("user_list", map[string]any{"age": 30}))
+	require.NoError(t, err)
+	assert.Greater(t, buildResult.ParseDuration, time.Duration(0))
+	assert.Greater(t, buildResult.ExecDuration, time.Duration(0))
+}
+
+func TestBiRepo_NilDB(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList, `SELECT * FROM users`)
+	repo := NewBiRepo()
+	req := newTestRequest("user_list", nil)
+
+	t.Run("Build tplDb 为 nil", func(t *testing.T) {
+		result, err := repo.Build(context.Background(), nil, req)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, biz.ErrNilDB)
+		assert.Contains(t, err.Error(), "tplDb")
+	})
+
+	t.Run("Execute tplDb 为 nil", func(t *testing.T) {
+		result, err := repo.Execute(context.Background(), nil, db, req)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, biz.ErrNilDB)
+		assert.Contains(t, err.Error(), "tplDb")
+	})
+
+	t.Run("Execute execDB 为 nil", func(t *testing.T) {
+		result, err := repo.Execute(context.Background(), db, nil, req)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, biz.ErrNilDB)
+		assert.Contains(t, err.Error(), "execDB")
+	})
+}
+
+func TestBiRepo_Build_StructParams(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_search", biz.OpTypeList,
+		`SELECT * FROM users WHERE {expr . "name" "IN" "params.names"} AND {expr . "age" ">=" "params.filter.min_age"} AND {expr . "age" "<=" "sys.max_age"}`)
+
+	type filter struct {
+		MinAge int `json:"min_age"`
+	}
+	type searchParams struct {
+		Names  []string `json:"names"`
+		Filter filter   `json:"filter"`
+		Ignore string   `json:"-"`
+	}
+
+	repo := NewBiRepo(WithStrictErrors(true))
+	req := newTestRequest("user_search", searchParams{
+		Names:  []string{"alice", "bob", "carol"},
+		Filter: filter{MinAge: 25},
+		Ignore: "ignored",
+	})
+	req.Sys = struct {
+		MaxAge int `json:"max_age"`
+	}{MaxAge: 35}
+
+	buildResult, err := repo.Build(context.Background(), db, req)
+	require.NoError(t, err)
+	assert.Empty(t, buildResult.SQLStmt.Errors)
+	assert.Equal(t, "SELECT * FROM users WHERE name IN (?, ?, ?) AND age >= ? AND age <= ?", buildResult.SQLStmt.SQL)
+	assert.Equal(t, []any{"alice", "bob", "carol", float64(25), float64(35)}, buildResult.SQLStmt.Args)
+
+	result, err := repo.Execute(context.Background(), db, db, req)
+	require.NoError(t, err)
+	data := result.Data.([]map[string]any)
+	require.Len(t, data, 1)
+	assert.Equal(t, "bob", data[0]["name"])
+}
+
+func TestBiRepo_Build_InvalidVars(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList, `SELECT * FROM users`)
+
+	repo := NewBiRepo()
+	req := newTestRequest("user_list", map[string]any{"ch": make(chan int)})
+
+	result, err := repo.Build(context.Background(), db, req)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, biz.ErrInvalidVars)
+	assert.Contains(t, err.Error(), "params")
+}
+
+func TestValidateTemplate(t *testing.T) {
+	t.Run("格式正确的模板", func(t *testing.T) {
+		errs, validatorErrs, err := ValidateTemplate(
+			`SELECT * FROM users WHERE {optExpr . "name" "=" "params.name"} {expr . "age" ">=" "params.age"}`,
+			map[string]any{"age": 18},
+		)
+		require.NoError(t, err)
+		assert.Empty(t, errs)
+		assert.Empty(t, validatorErrs)
+	})
+
+	t.Run("expr 参数不足", func(t *testing.T) {
+		errs, _, err := ValidateTemplate(`SELECT * FROM users WHERE {expr . "name"}`, nil)
+		require.NoError(t, err)
+		assert.Contains(t, errs, "expr: no values")
+	})
+
+	t.Run("缺少必填参数", func(t *testing.T) {
+		errs, validatorErrs, err := ValidateTemplate(
+			`{vRequired . "name" "name_required" "name is required" "params.name"}SELECT * FROM users WHERE {expr . "name" "=" "params.name"}`,
+			nil,
+		)
+		require.NoError(t, err)
+		assert.NotEmpty(t, errs)
+		require.Len(t, validatorErrs, 1)
+		assert.Equal(t, "name_required", validatorErrs[0].Code)
+	})
+
+	t.Run("语法错误", func(t *testing.T) {
+		_, _, err := ValidateTemplate(`SELECT * FROM users WHERE {expr . "name" "=" "params.name"`, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("未知函数", func(t *testing.T) {
+		_, _, err := ValidateTemplate(`SELECT * FROM users WHERE {unknownFunc . "name"}`, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestBiRepo_Build_StrictValidation(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_update", biz.OpTypeUpdate,
+		`{vRequired . "id" "id_required" "id is required" "params.id"}`+
+			`{vStr . "name" "name_str" "name must be string" "params.name"}`+
+			`UPDATE users SET name = {val . "params.name"} WHERE {expr . "id" "=" "params.id"}`)
+
+	invalid := map[string]any{"name": 123}
+	valid := map[string]any{"id": 1, "name": "alice2"}
+
+	t.Run("默认模式仍生成 SQL", func(t *testing.T) {
+		repo := NewBiRepo()
+		buildResult, err := repo.Build(context.Background(), db, newTestRequest("user_update", invalid))
+		require.NoError(t, err)
+		assert.Len(t, buildResult.SQLStmt.ValidatorsErrors, 2)
+		assert.NotEmpty(t, buildResult.SQLStmt.SQL)
+	})
+
+	t.Run("严格模式抑制 SQL", func(t *testing.T) {
+		repo := NewBiRepo(WithStrictValidation(true))
+		buildResult, err := repo.Build(context.Background(), db, newTestRequest("user_update", invalid))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, biz.ErrDSLValidationFailed)
+
+		var validationErr *biz.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.Errors, 2)
+		assert.Equal(t, "id_required", validationErr.Errors[0].Code)
+		assert.Equal(t, "name_str", validationErr.Errors[1].Code)
+
+		var ve *qsql.ValidatorError
+		assert.ErrorAs(t, err, &ve)
+
+		require.NotNil(t, buildResult)
+		assert.Empty(t, buildResult.SQLStmt.SQL)
+		assert.Empty(t, buildResult.SQLStmt.Args)
+
+		result, err := repo.Execute(context.Background(), db, db, newTestRequest("user_update", invalid))
+		assert.ErrorIs(t, err, biz.ErrDSLValidationFailed)
+		assert.Nil(t, result)
+
+		var name string
+		require.NoError(t, db.Raw("SELECT name FROM users WHERE id = 1").Scan(&name).Error)
+		assert.Equal(t, "alice", name)
+	})
+
+	t.Run("严格模式校验通过正常执行", func(t *testing.T) {
+		repo := NewBiRepo(WithStrictValidation(true))
+		result, err := repo.Execute(context.Background(), db, db, newTestRequest("user_update", valid))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.RowsAffected)
+	})
+}
+
+func TestBiRepo_Execute_TxWrites(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	// 第二条语句引用不存在的表，执行失败
+	createTestTemplate(t, db, "user_add_bad", biz.OpTypeAdd,
+		`INSERT INTO users (name, age) VALUES ({val . "params.name"}, 50); INSERT INTO not_exists (id) VALUES (1)`)
+	createTestTemplate(t, db, "user_update", biz.OpTypeUpdate,
+		`UPDATE users SET age = age + 1 WHERE age >= {val . "params.age"}`)
+
+	countUsers := func(name string) int64 {
+		var n int64
+		require.NoError(t, db.Table("users").Where("name = ?", name).Count(&n).Error)
+		return n
+	}
+
+	t.Run("事务模式失败时回滚", func(t *testing.T) {
+		repo := NewBiRepo(WithTxWrites(true))
+		result, err := repo.Execute(context.Background(), db, db, newTestRequest("user_add_bad", map[string]any{"name": "dave"}))
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, int64(0), countUsers("dave"))
+	})
+
+	t.Run("非事务模式不回滚", func(t *testing.T) {
+		repo := NewBiRepo()
+		_, err := repo.Execute(context.Background(), db, db, newTestRequest("user_add_bad", map[string]any{"name": "erin"}))
+		require.Error(t, err)
+		assert.Equal(t, int64(1), countUsers("erin"))
+	})
+
+	t.Run("事务模式保留受影响行数", func(t *testing.T) {
+		repo := NewBiRepo(WithTxWrites(true))
+		result, err := repo.Execute(context.Background(), db, db, newTestRequest("user_update", map[string]any{"age": 30}))
+		require.NoError(t, err)
+		assert.Equal(t, biz.OpTypeUpdate, result.OpType)
+		assert.Equal(t, int64(3), result.RowsAffected)
+	})
+}
+
+func TestBiRepo_Execute_ListCount(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	for i := 0; i < 7; i++ {
+		require.NoError(t, db.Exec("INSERT INTO users (name, age) VALUES (?, ?)", fmt.Sprintf("user%d", i), 50+i).Error)
+	}
+	content := `SELECT name, age FROM users WHERE age >= {val . "params.age"} ORDER BY age LIMIT {val . "params.offset"}, {val . "params.limit"}`
+	createTestTemplate(t, db, "user_paged", biz.OpTypeListPaged, content)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList, content)
+	params := map[string]any{"age": 30, "offset": 2, "limit": 3}
+
+	names := func(result *biz.ExecuteResult) []any {
+		data, ok := result.Data.([]map[string]any)
+		require.True(t, ok)
+		var out []any
+		for _, row := range data {
+			out = append(out, row["name"])
+		}
+		return out
+	}
+
+	t.Run("分页列表返回当前页与总数", func(t *testing.T) {
+		result, err := NewBiRepo().Execute(context.Background(), db, db, newTestRequest("user_paged", params))
+		require.NoError(t, err)
+		assert.Equal(t, biz.OpTypeListPaged, result.OpType)
+		// bob、carol 与新增的 7 条满足 age >= 30
+		assert.Equal(t, int64(9), result.Count)
+		assert.Equal(t, int64(3), result.RowsAffected)
+		assert.Equal(t, []any{"user0", "user1", "user2"}, names(result))
+	})
+
+	t.Run("普通列表默认不查询总数", func(t *testing.T) {
+		result, err := NewBiRepo().Execute(context.Background(), db, db, newTestRequest("user_list", params))
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.Count)
+		assert.Len(t, names(result), 3)
+	})
+
+	t.Run("普通列表开启 WithCount", func(t *testing.T) {
+		req := newTestRequest("user_list", params)
+		req.WithCount = true
+		result, err := NewBiRepo().Execute(context.Background(), db, db, req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), result.Count)
+		assert.Len(t, names(result), 3)
+	})
+
+	t.Run("ExecuteBuild 执行已构建的结果", func(t *testing.T) {
+		repo := NewBiRepo()
+		req := newTestRequest("user_paged", params)
+		buildResult, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+
+		result, err := repo.ExecuteBuild(context.Background(), db, req, buildResult)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), result.Count)
+		assert.Equal(t, []any{"user0", "user1", "user2"}, names(result))
+
+		result, err = repo.ExecuteBuild(context.Background(), db, req, nil)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, biz.ErrNilBuildResult)
+	})
+}
+
+func TestBiRepo_ContextCancelled(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList, `SELECT * FROM users`)
+	repo := NewBiRepo()
+	req := newTestRequest("user_list", nil)
+
+	// 统计 users 查询次数，确认取消后不会执行查询
+	var queried int
+	require.NoError(t, db.Callback().Row().Before("gorm:row").Register("test:count_row", func(tx *gorm.DB) {
+		queried++
+	}))
+
+	t.Run("已取消", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		buildResult, err := repo.Build(ctx, db, req)
+		assert.Nil(t, buildResult)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		result, err := repo.Execute(ctx, db, db, req)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, queried)
+	})
+
+	t.Run("已超时", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		result, err := repo.Execute(ctx, db, db, req)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, queried)
+	})
+
+	t.Run("未取消正常执行", func(t *testing.T) {
+		result, err := repo.Execute(context.Background(), db, db, req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.RowsAffected)
+		assert.Equal(t, 1, queried)
+	})
+}
+
+func TestBiRepo_MetricsHook(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList, `SELECT * FROM users WHERE age >= {val . "params.age"}`)
+	createTestTemplate(t, db, "user_update", biz.OpTypeUpdate, `UPDATE users SET age = age + 1 WHERE name = {val . "params.name"}`)
+
+	var got []*Metrics
+	repo := NewBiRepo(WithMetricsHook(func(ctx context.Context, m *Metrics) {
+		got = append(got, m)
+	}))
+
+	_, err := repo.Execute(context.Background(), db, db, newTestRequest("user_list", map[string]any{"age": 30}))
+	require.NoError(t, err)
+	_, err = repo.Execute(context.Background(), db, db, newTestRequest("user_update", map[string]any{"name": "alice"}))
+	require.NoError(t, err)
+	_, err = repo.Execute(context.Background(), db, db, newTestRequest("not_exists", nil))
+	require.Error(t, err)
+
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "user_list", got[0].Code)
+	assert.Equal(t, biz.OpTypeList, got[0].OpType)
+	assert.Greater(t, got[0].ParseDuration, time.Duration(0))
+	assert.Greater(t, got[0].BuildDuration, time.Duration(0))
+	assert.Greater(t, got[0].ExecuteDuration, time.Duration(0))
+	assert.Equal(t, int64(2), got[0].RowsAffected)
+	assert.NoError(t, got[0].Err)
+
+	assert.Equal(t, biz.OpTypeUpdate, got[1].OpType)
+	assert.Greater(t, got[1].ExecuteDuration, time.Duration(0))
+	assert.Equal(t, int64(1), got[1].RowsAffected)
+
+	// 构建失败同样回调
+	assert.Equal(t, "not_exists", got[2].Code)
+	assert.Equal(t, 0, got[2].OpType)
+	assert.Error(t, got[2].Err)
+}
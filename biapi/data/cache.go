@@ -0,0 +1,90 @@
+package data
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/qq1060656096/bizutil/qsql"
+)
+
+// defaultTemplateCacheSize 模板缓存默认容量。
+const defaultTemplateCacheSize = 256
+
+// templateCache 已解析模板的 LRU 缓存，并发安全。
+//
+// 缓存以模板数据为单位，同时记录 checksum，checksum 变化时视为未命中并重新解析。
+//
+// qsql.Engine 文档声明并发不安全，但缓存的 Engine 只在 Parse 后被读取：
+// Execute 的执行状态（参数、错误等）每次调用独立创建，text/template 的 Execute 支持并发调用，
+// 因此同一 Engine 可被多个请求并发执行；缓存的 Engine 不得再调用 Parse。
+type templateCache struct {
+	size int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// templateCacheEntry 缓存条目。
+type templateCacheEntry struct {
+	key      string
+	checksum string
+	engine   *qsql.Engine
+}
+
+// newTemplateCache 创建容量为 size 的模板缓存。
+func newTemplateCache(size int) *templateCache {
+	return &templateCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get 返回 key 对应且 checksum 一致的已解析模板。
+func (c *templateCache) get(key, checksum string) (*qsql.Engine, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*templateCacheEntry)
+	if entry.checksum != checksum {
+		// checksum 变化说明模板内容已更新，旧的解析结果失效
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return entry.engine, true
+}
+
+// add 缓存已解析模板，超过容量时淘汰最久未使用的条目。
+func (c *templateCache) add(key, checksum string, engine *qsql.Engine) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*templateCacheEntry)
+		entry.checksum = checksum
+		entry.engine = engine
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&templateCacheEntry{key: key, checksum: checksum, engine: engine})
+	for c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*templateCacheEntry).key)
+	}
+}
+
+// len 返回缓存条目数。
+func (c *templateCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -0,0 +1,155 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/qq1060656096/bizutil/qsql"
+	"github.com/qq1060656096/drugo-provider/biapi/biz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateCache(t *testing.T) {
+	c := newTemplateCache(2)
+	e1, e2, e3 := qsql.NewEngine(), qsql.NewEngine(), qsql.NewEngine()
+
+	c.add("a", "c1", e1)
+	got, ok := c.get("a", "c1")
+	require.True(t, ok)
+	assert.Same(t, e1, got)
+
+	// checksum 变化时失效
+	_, ok = c.get("a", "c2")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.len())
+
+	// 超过容量时淘汰最久未使用的条目
+	c.add("a", "c1", e1)
+	c.add("b", "c1", e2)
+	c.get("a", "c1")
+	c.add("c", "c1", e3)
+	assert.Equal(t, 2, c.len())
+	_, ok = c.get("b", "c1")
+	assert.False(t, ok)
+	_, ok = c.get("a", "c1")
+	assert.True(t, ok)
+	_, ok = c.get("c", "c1")
+	assert.True(t, ok)
+}
+
+func TestTemplateCache_Concurrent(t *testing.T) {
+	c := newTemplateCache(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := string(rune('a' + i%10))
+			for j := 0; j < 100; j++ {
+				if _, ok := c.get(key, "c"); !ok {
+					c.add(key, "c", qsql.NewEngine())
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, c.len(), 8)
+}
+
+func TestBiRepo_Build_TemplateCache(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList, `SELECT * FROM users WHERE age >= {val . "params.age"}`)
+	setContent := func(content, checksum string) {
+		require.NoError(t, db.Model(&TemplateData{}).Where("td_id = 1").
+			Updates(map[string]any{"content": content, "checksum": checksum}).Error)
+	}
+	req := newTestRequest("user_list", map[string]any{"age": 30})
+
+	t.Run("checksum 不变时使用缓存", func(t *testing.T) {
+		repo := NewBiRepo()
+		setContent(`SELECT * FROM users WHERE age >= {val . "params.age"}`, "v1")
+
+		first, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+		assert.Equal(t, 1, repo.tplCache.len())
+
+		// 内容变化但 checksum 未变，仍使用缓存的解析结果
+		setContent(`SELECT id FROM users WHERE age >= {val . "params.age"}`, "v1")
+		second, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+		assert.Equal(t, first.SQLStmt.SQL, second.SQLStmt.SQL)
+		assert.Equal(t, 1, repo.tplCache.len())
+
+		// checksum 变化时重新解析
+		setContent(`SELECT id FROM users WHERE age >= {val . "params.age"}`, "v2")
+		third, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+		assert.Contains(t, third.SQLStmt.SQL, "SELECT id FROM users")
+		assert.Equal(t, []any{float64(30)}, third.SQLStmt.Args)
+	})
+
+	t.Run("checksum 为空时不缓存", func(t *testing.T) {
+		repo := NewBiRepo()
+		setContent(`SELECT * FROM users WHERE age >= {val . "params.age"}`, "")
+
+		_, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+		assert.Equal(t, 0, repo.tplCache.len())
+	})
+
+	t.Run("关闭缓存", func(t *testing.T) {
+		repo := NewBiRepo(WithTemplateCacheSize(0))
+		setContent(`SELECT * FROM users WHERE age >= {val . "params.age"}`, "v1")
+
+		_, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+		setContent(`SELECT id FROM users WHERE age >= {val . "params.age"}`, "v1")
+		result, err := repo.Build(context.Background(), db, req)
+		require.NoError(t, err)
+		assert.Contains(t, result.SQLStmt.SQL, "SELECT id FROM users")
+		assert.Nil(t, repo.tplCache)
+	})
+}
+
+func TestBiRepo_Build_TemplateCache_Concurrent(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeList,
+		`{vRequired . "name" "name_required" "name is required" "params.name"}SELECT * FROM users WHERE age >= {val . "params.age"}`)
+	require.NoError(t, db.Model(&TemplateData{}).Where("td_id = 1").Update("checksum", "v1").Error)
+
+	repo := NewBiRepo()
+	_, err := repo.Build(context.Background(), db, newTestRequest("user_list", map[string]any{"age": 0, "name": "alice"}))
+	require.NoError(t, err)
+	require.Equal(t, 1, repo.tplCache.len())
+
+	// 多个请求并发执行同一个缓存的 Engine，参数与校验错误互不串扰（需配合 -race 运行）
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			params := map[string]any{"age": i}
+			if i%2 == 0 {
+				params["name"] = "alice"
+			}
+			for j := 0; j < 20; j++ {
+				result, err := repo.Build(context.Background(), db, newTestRequest("user_list", params))
+				if !assert.NoError(t, err) {
+					return
+				}
+				if i%2 == 1 {
+					assert.True(t, result.SQLStmt.HasValidatorErrors())
+					continue
+				}
+				assert.False(t, result.SQLStmt.HasValidatorErrors())
+				assert.Equal(t, []any{float64(i)}, result.SQLStmt.Args)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 1, repo.tplCache.len())
+}
@@ -0,0 +1,106 @@
+package data
+
+import (
+	"strings"
+	"unicode"
+)
+
+// buildCountSQL 根据列表查询 SQL 派生 COUNT 查询。
+//
+// 去掉最外层末尾的 ORDER BY / LIMIT / OFFSET 子句后包装为
+// SELECT COUNT(*) FROM (<sql>) t，并丢弃被去掉部分中占位符对应的参数，
+// 其余参数与原查询保持一致。
+func buildCountSQL(sql string, args []any) (string, []any) {
+	body := strings.TrimRightFunc(sql, func(r rune) bool {
+		return r == ';' || unicode.IsSpace(r)
+	})
+
+	if cut := tailClauseIndex(body); cut >= 0 {
+		removed := countPlaceholders(body[cut:])
+		body = strings.TrimRightFunc(body[:cut], unicode.IsSpace)
+		if removed > len(args) {
+			removed = len(args)
+		}
+		args = args[:len(args)-removed]
+	}
+
+	countArgs := make([]any, len(args))
+	copy(countArgs, args)
+	return "SELECT COUNT(*) FROM (" + body + ") t", countArgs
+}
+
+// tailClauseIndex 返回最外层（不在括号和引号内）第一个 ORDER BY / LIMIT / OFFSET 的位置，
+// 不存在时返回 -1。
+func tailClauseIndex(sql string) int {
+	upper := strings.ToUpper(sql)
+	depth := 0
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch ch {
+		case '\'', '"', '`':
+			quote = ch
+			continue
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth--
+			continue
+		}
+		if depth != 0 || (i > 0 && isIdentChar(sql[i-1])) {
+			continue
+		}
+		if matchKeyword(upper, i, "LIMIT") || matchKeyword(upper, i, "OFFSET") {
+			return i
+		}
+		if matchKeyword(upper, i, "ORDER") {
+			rest := strings.TrimLeftFunc(upper[i+len("ORDER"):], unicode.IsSpace)
+			if strings.HasPrefix(rest, "BY") && (len(rest) == 2 || !isIdentChar(rest[2])) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// matchKeyword 判断 upper[i:] 是否以完整关键字 kw 开头。
+func matchKeyword(upper string, i int, kw string) bool {
+	if !strings.HasPrefix(upper[i:], kw) {
+		return false
+	}
+	end := i + len(kw)
+	return end == len(upper) || !isIdentChar(upper[end])
+}
+
+// countPlaceholders 统计不在引号内的 ? 占位符数量。
+func countPlaceholders(sql string) int {
+	n := 0
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch ch {
+		case '\'', '"', '`':
+			quote = ch
+		case '?':
+			n++
+		}
+	}
+	return n
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
@@ -0,0 +1,60 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCountSQL(t *testing.T) {
+	tests := []struct {
+		name         string
+		sql          string
+		args         []any
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name:         "无 ORDER BY / LIMIT",
+			sql:          "SELECT * FROM users WHERE age >= ?;",
+			args:         []any{18},
+			expectedSQL:  "SELECT COUNT(*) FROM (SELECT * FROM users WHERE age >= ?) t",
+			expectedArgs: []any{18},
+		},
+		{
+			name:         "去掉 ORDER BY 与 LIMIT 参数",
+			sql:          "SELECT * FROM users WHERE age >= ? ORDER BY id DESC LIMIT ? OFFSET ?",
+			args:         []any{18, 10, 20},
+			expectedSQL:  "SELECT COUNT(*) FROM (SELECT * FROM users WHERE age >= ?) t",
+			expectedArgs: []any{18},
+		},
+		{
+			name:         "小写关键字与字面量 LIMIT",
+			sql:          "select * from users where name = ? order by id limit 10",
+			args:         []any{"a"},
+			expectedSQL:  "SELECT COUNT(*) FROM (select * from users where name = ?) t",
+			expectedArgs: []any{"a"},
+		},
+		{
+			name:         "忽略子查询与字符串中的关键字",
+			sql:          "SELECT *, ROW_NUMBER() OVER (ORDER BY id) rn FROM users WHERE note = 'x limit ?' AND id IN (SELECT id FROM t ORDER BY id LIMIT ?) ORDER BY rn",
+			args:         []any{5},
+			expectedSQL:  "SELECT COUNT(*) FROM (SELECT *, ROW_NUMBER() OVER (ORDER BY id) rn FROM users WHERE note = 'x limit ?' AND id IN (SELECT id FROM t ORDER BY id LIMIT ?)) t",
+			expectedArgs: []any{5},
+		},
+		{
+			name:         "忽略标识符中的关键字",
+			sql:          "SELECT order_by, limited FROM users",
+			expectedSQL:  "SELECT COUNT(*) FROM (SELECT order_by, limited FROM users) t",
+			expectedArgs: []any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := buildCountSQL(tt.sql, tt.args)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qq1060656096/drugo-provider/biapi/biz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testUser struct {
+	ID   int64  `gorm:"column:id"`
+	Name string `gorm:"column:name"`
+	Age  int    `gorm:"column:age"`
+}
+
+func TestExecuteInto(t *testing.T) {
+	setupTestApp(t)
+	db := setupTestDB(t)
+	createTestTemplate(t, db, "user_list", biz.OpTypeListPaged,
+		`SELECT id, name, age FROM users WHERE age >= {val . "params.age"} ORDER BY age LIMIT 1`)
+	createTestTemplate(t, db, "user_detail", biz.OpTypeDetail,
+		`SELECT id, name, age FROM users WHERE name = {val . "params.name"}`)
+	createTestTemplate(t, db, "user_count", biz.OpTypeCount, `SELECT COUNT(*) FROM users`)
+	repo := NewBiRepo()
+
+	t.Run("列表", func(t *testing.T) {
+		users, result, err := ExecuteInto[testUser](context.Background(), repo, db, db, newTestRequest("user_list", map[string]any{"age": 30}))
+		require.NoError(t, err)
+		assert.Equal(t, []testUser{{ID: 2, Name: "bob", Age: 30}}, users)
+		assert.Equal(t, users, result.Data)
+		assert.Equal(t, int64(1), result.RowsAffected)
+		assert.Equal(t, int64(2), result.Count)
+	})
+
+	t.Run("列表无数据", func(t *testing.T) {
+		users, _, err := ExecuteInto[testUser](context.Background(), repo, db, db, newTestRequest("user_list", map[string]any{"age": 100}))
+		require.NoError(t, err)
+		assert.NotNil(t, users)
+		assert.Empty(t, users)
+	})
+
+	t.Run("详情", func(t *testing.T) {
+		users, result, err := ExecuteInto[testUser](context.Background(), repo, db, db, newTestRequest("user_detail", map[string]any{"name": "carol"}))
+		require.NoError(t, err)
+		assert.Equal(t, []testUser{{ID: 3, Name: "carol", Age: 40}}, users)
+		assert.Equal(t, biz.OpTypeDetail, result.OpType)
+
+		users, _, err = ExecuteInto[testUser](context.Background(), repo, db, db, newTestRequest("user_detail", map[string]any{"name": "nobody"}))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("非查询操作只返回元数据", func(t *testing.T) {
+		users, result, err := ExecuteInto[testUser](context.Background(), repo, db, db, newTestRequest("user_count", nil))
+		require.NoError(t, err)
+		assert.Nil(t, users)
+		assert.Equal(t, int64(3), result.Count)
+	})
+
+	t.Run("map 结果保持不变", func(t *testing.T) {
+		result, err := repo.Execute(context.Background(), db, db, newTestRequest("user_detail", map[string]any{"name": "alice"}))
+		require.NoError(t, err)
+		detail, ok := result.Data.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "alice", detail["name"])
+	})
+}
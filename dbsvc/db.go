@@ -3,20 +3,30 @@ package dbsvc
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/qq1060656096/drugo/kernel"
 	"github.com/qq1060656096/mgorm"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 const Name = "db"
 
+// 默认的启动 ping 重试间隔与关闭超时时间。
+const (
+	defaultPingRetryInterval = time.Second
+	defaultCloseTimeout      = 30 * time.Second
+)
+
 // 编译时检查，确保 DbService 实现了 kernel.Service 接口。
 var _ kernel.Service = (*DbService)(nil)
 
@@ -29,6 +39,9 @@ type DbService struct {
 
 	once    sync.Once
 	bootErr error
+
+	slowMu       sync.RWMutex
+	slowHandlers []func(info SlowQueryInfo)
 }
 
 // NewDbService 创建一个新的 DbService，默认名称为 "db"。
@@ -124,29 +137,73 @@ func (s *DbService) registerDB(ctx context.Context, groupName, dbName string) er
 		zap.String("driver", cfg.DriverType),
 	)
 
-	s.manager.MustGroup(groupName).Register(ctx, dbName, cfg)
-	err = s.manager.MustGroup(groupName).Ping(ctx, dbName)
+	group := s.manager.MustGroup(groupName)
+	group.Register(ctx, dbName, cfg)
+
+	if dbCfg.IsSet("ping_on_boot") && !dbCfg.GetBool("ping_on_boot") {
+		s.logger.Info("database registered without ping",
+			zap.String("group", groupName),
+			zap.String("db", dbName),
+		)
+		return nil
+	}
+
+	retryInterval := defaultPingRetryInterval
+	if dbCfg.IsSet("ping_retry_interval") {
+		retryInterval = dbCfg.GetDuration("ping_retry_interval")
+	}
+	err = s.pingWithRetry(ctx, func(ctx context.Context) error {
+		return group.Ping(ctx, dbName)
+	}, dbCfg.GetInt("ping_retries"), retryInterval)
 	if err != nil {
-		s.logger.Error("failed to ping db", zap.String("group", groupName), zap.String("db", dbName))
+		s.logger.Error("failed to ping db", zap.String("group", groupName), zap.String("db", dbName), zap.Error(err))
+		return err
 	}
 	s.logger.Info("database registered",
 		zap.String("group", groupName),
 		zap.String("db", dbName),
 	)
 
+	return nil
+}
+
+// pingWithRetry 执行 ping，失败后按指数退避最多重试 retries 次。
+// 每次重试的等待时间从 interval 开始翻倍；ctx 取消时立即返回。
+func (s *DbService) pingWithRetry(ctx context.Context, ping func(ctx context.Context) error, retries int, interval time.Duration) error {
+	err := ping(ctx)
+	for attempt := 1; err != nil && attempt <= retries; attempt++ {
+		s.logger.Warn("ping db failed, retrying",
+			zap.Int("attempt", attempt),
+			zap.Int("retries", retries),
+			zap.Duration("backoff", interval),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		err = ping(ctx)
+		interval *= 2
+	}
 	return err
 }
 
 // buildDBConfig 从 viper 配置创建 mgorm.DBConfig。
+// dsn、user、password 支持 ${VAR} 形式引用环境变量。
 func (s *DbService) buildDBConfig(v *viper.Viper) (mgorm.DBConfig, error) {
 	cfg := mgorm.DBConfig{
 		Name:            v.GetString("name"),
 		DriverType:      v.GetString("driver_type"),
-		DSN:             v.GetString("dsn"),
+		DSN:             s.expandEnv(v.GetString("dsn")),
 		Host:            v.GetString("host"),
 		Port:            v.GetInt("port"),
-		User:            v.GetString("user"),
-		Password:        v.GetString("password"),
+		User:            s.expandEnv(v.GetString("user")),
+		Password:        s.expandEnv(v.GetString("password")),
 		DBName:          v.GetString("db_name"),
 		Charset:         v.GetString("charset"),
 		MaxIdleConns:    v.GetInt("max_idle_conns"),
@@ -154,39 +211,339 @@ func (s *DbService) buildDBConfig(v *viper.Viper) (mgorm.DBConfig, error) {
 		ConnMaxLifetime: v.GetDuration("conn_max_lifetime"),
 	}
 	if cfg.DSN == "" {
-		cfg.DSN = cfg.AutoDsn()
+		cfg.DSN = autoDsn(cfg)
 	}
 
 	dialector, err := s.createDialector(cfg.DriverType, cfg.DSN)
 	if err != nil {
 		return mgorm.DBConfig{}, fmt.Errorf("create dialector: %w", err)
 	}
-	cfg.Dialector = dialector
+	gormOpts, err := s.buildGormOptions(v, cfg)
+	if err != nil {
+		return mgorm.DBConfig{}, err
+	}
+	cfg.Dialector = newGormConfigDialector(dialector, gormOpts...)
 
 	return cfg, nil
 }
 
+// buildGormOptions 从 viper 配置创建 gorm.Config 选项。
+//
+// 支持的配置项：
+//   - log_level：GORM 日志级别（silent/error/warn/info），默认 warn
+//   - slow_threshold：慢查询阈值，默认 200ms，<= 0 时不记录慢查询也不触发 OnSlowQuery 回调
+//   - replicas：只读副本 DSN 列表，配置后启用 dbresolver 读写分离
+//   - table_prefix：表名前缀
+//   - singular_table：是否使用单数表名，默认 false
+//   - skip_default_transaction：是否跳过写操作的默认事务，默认 false
+//   - prepare_stmt：是否缓存预编译语句，默认 false
+func (s *DbService) buildGormOptions(v *viper.Viper, cfg mgorm.DBConfig) ([]func(*gorm.Config), error) {
+	level, err := parseLogLevel(v.GetString("log_level"))
+	if err != nil {
+		return nil, err
+	}
+	slowThreshold := defaultSlowThreshold
+	if v.IsSet("slow_threshold") {
+		slowThreshold = v.GetDuration("slow_threshold")
+	}
+	gl := newGormLogger(s.logger, level, slowThreshold)
+
+	opts := []func(*gorm.Config){
+		func(c *gorm.Config) { c.Logger = gl },
+	}
+	if slowThreshold > 0 {
+		opts = append(opts, func(c *gorm.Config) {
+			if c.Plugins == nil {
+				c.Plugins = map[string]gorm.Plugin{}
+			}
+			c.Plugins[slowQueryPluginName] = &slowQueryPlugin{service: s, threshold: slowThreshold}
+		})
+	}
+
+	tablePrefix := v.GetString("table_prefix")
+	singularTable := v.GetBool("singular_table")
+	if tablePrefix != "" || singularTable {
+		opts = append(opts, func(c *gorm.Config) {
+			c.NamingStrategy = schema.NamingStrategy{
+				TablePrefix:         tablePrefix,
+				SingularTable:       singularTable,
+				IdentifierMaxLength: 64,
+			}
+		})
+	}
+
+	skipDefaultTransaction := v.GetBool("skip_default_transaction")
+	prepareStmt := v.GetBool("prepare_stmt")
+	opts = append(opts, func(c *gorm.Config) {
+		c.SkipDefaultTransaction = skipDefaultTransaction
+		c.PrepareStmt = prepareStmt
+	})
+
+	if replicas := v.GetStringSlice("replicas"); len(replicas) > 0 {
+		opt, err := s.buildResolverOption(cfg, replicas)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts, nil
+}
+
+// autoDsn 根据 host、port 等字段生成 DSN，tidb 按 mysql 格式生成。
+func autoDsn(cfg mgorm.DBConfig) string {
+	if cfg.DriverType == "tidb" {
+		cfg.DriverType = "mysql"
+	}
+	return cfg.AutoDsn()
+}
+
 // createDialector 根据指定的驱动类型创建 gorm Dialector。
 func (s *DbService) createDialector(driverType, dsn string) (gorm.Dialector, error) {
 	return CreateDialector(driverType, dsn)
 }
 
 // Close 释放此服务管理的所有数据库连接。
+//
+// 各数据库并发关闭底层 *sql.DB，整体受 close_timeout（默认 30s）限制；
+// 返回的错误按 "group.name" 标识关闭失败或超时的数据库。
+// 尚未建立连接的数据库会被惰性打开后关闭。
 func (s *DbService) Close(ctx context.Context) error {
 	if s.manager == nil {
 		return nil
 	}
-	// TODO: 当 mgorm 支持时，实现正确的连接清理
-	errs := s.manager.Close(ctx)
+	logger := s.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	timeout := defaultCloseTimeout
+	if s.config != nil && s.config.IsSet("close_timeout") {
+		timeout = s.config.GetDuration("close_timeout")
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		total int
+		errs  []error
+	)
+	for _, groupName := range s.manager.ListGroupNames() {
+		group, err := s.manager.Group(groupName)
+		if err != nil {
+			continue
+		}
+		for _, dbName := range group.List() {
+			total++
+			wg.Add(1)
+			go func(group mgorm.Group, groupName, dbName string) {
+				defer wg.Done()
+				if err := closeWithContext(ctx, func() error {
+					return closeDB(ctx, group, dbName)
+				}); err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("close db %s.%s: %w", groupName, dbName, err))
+					mu.Unlock()
+				}
+			}(group, groupName, dbName)
+		}
+	}
+	wg.Wait()
+
+	// 清空注册表，*sql.DB.Close 是幂等的，已关闭的连接不会重复关闭；
+	// 超时时不再等待，避免被未完成的关闭阻塞
+	_ = closeWithContext(ctx, func() error {
+		s.manager.Close(ctx)
+		return nil
+	})
+
 	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
 		err := errors.Join(errs...)
-		s.logger.Error("mgorm service failed to close", zap.Error(err))
+		logger.Error("mgorm service failed to close",
+			zap.Int("total", total),
+			zap.Int("failed", len(errs)),
+			zap.Error(err),
+		)
 		return err
 	}
-	s.logger.Info("mgorm service closed")
+	logger.Info("mgorm service closed", zap.Int("total", total))
 	return nil
 }
 
+// closeDB 获取数据库连接并关闭其底层 *sql.DB。
+//
+// 不使用 group.Unregister：它会忽略关闭错误，且关闭期间持有 Manager 的全局锁。
+func closeDB(ctx context.Context, group mgorm.Group, name string) error {
+	db, err := group.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// closeWithContext 执行 closeFn，ctx 结束前未完成时返回 ctx.Err()。
+func closeWithContext(ctx context.Context, closeFn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- closeFn()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DB 返回指定分组下的数据库连接。
+// 服务未启动时返回 ErrNotBooted；分组或数据库未注册时返回的错误
+// 可通过 errors.Is(err, registry.ErrGroupNotFound / registry.ErrResourceNotFound) 判断。
+func (s *DbService) DB(ctx context.Context, group, name string) (*gorm.DB, error) {
+	if s.manager == nil {
+		return nil, ErrNotBooted
+	}
+	g, err := s.manager.Group(group)
+	if err != nil {
+		return nil, err
+	}
+	return g.Get(ctx, name)
+}
+
+// MustDB 与 DB 相同，但获取失败时 panic。
+func (s *DbService) MustDB(ctx context.Context, group, name string) *gorm.DB {
+	db, err := s.DB(ctx, group, name)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// Default 返回默认数据库连接。
+//
+// 优先使用配置项 default_group / default_db；未配置 default_db 时，
+// 若分组（default_group 指定的分组或全部分组）下只注册了一个数据库则自动推断。
+// 无法唯一确定时返回 ErrNoDefaultDB。
+func (s *DbService) Default(ctx context.Context) (*gorm.DB, error) {
+	if s.manager == nil {
+		return nil, ErrNotBooted
+	}
+	group, name, err := s.defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return s.DB(ctx, group, name)
+}
+
+// defaultDB 解析默认数据库所在的分组与名称。
+func (s *DbService) defaultDB() (string, string, error) {
+	var group, name string
+	if s.config != nil {
+		group = s.config.GetString("default_group")
+		name = s.config.GetString("default_db")
+	}
+	if group != "" && name != "" {
+		return group, name, nil
+	}
+
+	groups := s.manager.ListGroupNames()
+	if group != "" {
+		groups = []string{group}
+	}
+
+	var candidates []string
+	for _, groupName := range groups {
+		g, err := s.manager.Group(groupName)
+		if err != nil {
+			return "", "", err
+		}
+		for _, dbName := range g.List() {
+			if name == "" || dbName == name {
+				candidates = append(candidates, groupName+"."+dbName)
+			}
+		}
+	}
+	if len(candidates) != 1 {
+		sort.Strings(candidates)
+		return "", "", fmt.Errorf("%w: %d candidates %v, set default_group/default_db", ErrNoDefaultDB, len(candidates), candidates)
+	}
+
+	group, name, _ = strings.Cut(candidates[0], ".")
+	return group, name, nil
+}
+
+// Stats 返回所有已注册数据库的连接池统计信息，键为 "group.name"。
+// 尚未建立连接的数据库会被惰性打开；获取失败的数据库不包含在结果中。
+// 服务未启动时返回空 map。
+func (s *DbService) Stats() map[string]sql.DBStats {
+	stats := make(map[string]sql.DBStats)
+	if s.manager == nil {
+		return stats
+	}
+
+	ctx := context.Background()
+	for _, groupName := range s.manager.ListGroupNames() {
+		group, err := s.manager.Group(groupName)
+		if err != nil {
+			continue
+		}
+		for _, dbName := range group.List() {
+			db, err := group.Get(ctx, dbName)
+			if err != nil {
+				s.logger.Warn("failed to get db stats", zap.String("group", groupName), zap.String("db", dbName), zap.Error(err))
+				continue
+			}
+			sqlDB, err := db.DB()
+			if err != nil {
+				s.logger.Warn("failed to get db stats", zap.String("group", groupName), zap.String("db", dbName), zap.Error(err))
+				continue
+			}
+			stats[groupName+"."+dbName] = sqlDB.Stats()
+		}
+	}
+	return stats
+}
+
+// Ping 检查指定数据库连接是否可用，直接 Ping 底层 *sql.DB，适用于就绪探针。
+// 服务未启动时返回 ErrNotBooted；分组或数据库未注册时返回与 DB 相同的错误。
+func (s *DbService) Ping(ctx context.Context, group, name string) error {
+	db, err := s.DB(ctx, group, name)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PingAll 检查所有已注册数据库的连接，键为 "group.name"，值为 Ping 结果（成功为 nil）。
+// 服务未启动时返回空 map。
+func (s *DbService) PingAll(ctx context.Context) map[string]error {
+	results := make(map[string]error)
+	if s.manager == nil {
+		return results
+	}
+
+	for _, groupName := range s.manager.ListGroupNames() {
+		group, err := s.manager.Group(groupName)
+		if err != nil {
+			continue
+		}
+		for _, dbName := range group.List() {
+			results[groupName+"."+dbName] = s.Ping(ctx, groupName, dbName)
+		}
+	}
+	return results
+}
+
 // Manager 返回底层的 mgorm.Manager 实例。
 // 如果 Boot 尚未被调用，则返回 nil。
 func (s *DbService) Manager() mgorm.Manager {
@@ -3,7 +3,12 @@ package dbsvc
 import (
 	"errors"
 	"fmt"
+	"os"
+	"regexp"
 
+	"go.uber.org/zap"
+
+	"gorm.io/driver/clickhouse"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -14,9 +19,17 @@ import (
 // ErrUnknownDriverType 当指定了不支持的数据库驱动类型时返回此错误。
 var ErrUnknownDriverType = errors.New("mgormsvc: unknown driver type")
 
+// ErrNotBooted 在服务尚未 Boot 时获取数据库连接返回此错误。
+var ErrNotBooted = errors.New("mgormsvc: service not booted")
+
+// ErrNoDefaultDB 未配置 default_group/default_db 且无法唯一推断默认数据库时返回此错误。
+var ErrNoDefaultDB = errors.New("mgormsvc: no default db")
+
+// CreateDialector 根据驱动类型创建 gorm Dialector。
+// 支持 mysql、tidb（使用 mysql 驱动）、postgres、sqlite、sqlserver、clickhouse。
 func CreateDialector(driverType, dsn string) (gorm.Dialector, error) {
 	switch driverType {
-	case "mysql":
+	case "mysql", "tidb":
 		return mysql.Open(dsn), nil
 	case "postgres":
 		return postgres.Open(dsn), nil
@@ -24,7 +37,25 @@ func CreateDialector(driverType, dsn string) (gorm.Dialector, error) {
 		return sqlite.Open(dsn), nil
 	case "sqlserver":
 		return sqlserver.Open(dsn), nil
+	case "clickhouse":
+		return clickhouse.Open(dsn), nil
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnknownDriverType, driverType)
 	}
 }
+
+// envVarPattern 匹配 ${VAR} 形式的环境变量引用。
+// 只支持花括号形式，避免误替换密码等字段中的 $ 字符。
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv 将 s 中的 ${VAR} 替换为对应的环境变量值，未设置的变量替换为空并记录警告。
+func (s *DbService) expandEnv(value string) string {
+	return envVarPattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := match[2 : len(match)-1]
+		env, ok := os.LookupEnv(name)
+		if !ok && s.logger != nil {
+			s.logger.Warn("environment variable not set", zap.String("name", name))
+		}
+		return env
+	})
+}
@@ -0,0 +1,86 @@
+package dbsvc
+
+import (
+	"fmt"
+
+	"github.com/qq1060656096/mgorm"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// gormConfigDialector 包装 gorm.Dialector，用于向 gorm.Open 注入自定义 gorm.Config。
+//
+// mgorm 打开连接时固定使用 &gorm.Config{}，而 gorm.Open 会调用实现了
+// Apply(*gorm.Config) 的 Dialector，借此设置日志、命名策略、插件等配置；
+// Initialize 时将 db.Dialector 还原为原始 Dialector，避免影响 SavePoint、
+// ErrorTranslator 等基于类型断言的能力。
+type gormConfigDialector struct {
+	gorm.Dialector
+	options []func(*gorm.Config)
+}
+
+// newGormConfigDialector 创建包装后的 Dialector，options 为空时直接返回原始 Dialector。
+func newGormConfigDialector(dialector gorm.Dialector, options ...func(*gorm.Config)) gorm.Dialector {
+	if len(options) == 0 {
+		return dialector
+	}
+	return &gormConfigDialector{
+		Dialector: dialector,
+		options:   options,
+	}
+}
+
+// Apply 在 gorm.Open 时应用自定义配置。
+func (d *gormConfigDialector) Apply(config *gorm.Config) error {
+	if inner, ok := d.Dialector.(interface{ Apply(*gorm.Config) error }); ok {
+		if err := inner.Apply(config); err != nil {
+			return err
+		}
+	}
+	for _, opt := range d.options {
+		opt(config)
+	}
+	return nil
+}
+
+// Initialize 还原原始 Dialector 并完成初始化。
+func (d *gormConfigDialector) Initialize(db *gorm.DB) error {
+	db.Dialector = d.Dialector
+	return d.Dialector.Initialize(db)
+}
+
+// buildResolverOption 创建读写分离选项：写操作使用主库，读操作路由到 replicas。
+//
+// 每次打开连接都会创建新的 dbresolver 实例，避免多次 Open（如 Ping）之间共享已初始化的插件。
+// 副本连接池沿用主库的连接池配置。
+func (s *DbService) buildResolverOption(cfg mgorm.DBConfig, replicas []string) (func(*gorm.Config), error) {
+	dialectors := make([]gorm.Dialector, 0, len(replicas))
+	for _, dsn := range replicas {
+		dialector, err := s.createDialector(cfg.DriverType, s.expandEnv(dsn))
+		if err != nil {
+			return nil, fmt.Errorf("create replica dialector: %w", err)
+		}
+		dialectors = append(dialectors, dialector)
+	}
+
+	return func(c *gorm.Config) {
+		resolver := dbresolver.Register(dbresolver.Config{
+			Replicas: dialectors,
+			Policy:   dbresolver.RandomPolicy{},
+		})
+		if cfg.MaxIdleConns > 0 {
+			resolver.SetMaxIdleConns(cfg.MaxIdleConns)
+		}
+		if cfg.MaxOpenConns > 0 {
+			resolver.SetMaxOpenConns(cfg.MaxOpenConns)
+		}
+		if cfg.ConnMaxLifetime > 0 {
+			resolver.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		}
+
+		if c.Plugins == nil {
+			c.Plugins = map[string]gorm.Plugin{}
+		}
+		c.Plugins[resolver.Name()] = resolver
+	}, nil
+}
@@ -0,0 +1,129 @@
+package dbsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// traceIDKey 请求上下文中 trace id 的键名，与 ginsrv.TraceIDKey 保持一致。
+const traceIDKey = "trace_id"
+
+// 默认的 GORM 日志配置。
+const (
+	defaultLogLevel      = logger.Warn
+	defaultSlowThreshold = 200 * time.Millisecond
+)
+
+// 编译时检查，确保 gormLogger 实现了 logger.Interface 接口。
+var _ logger.Interface = (*gormLogger)(nil)
+
+// gormLogger 基于 zap 的 GORM 日志实现。
+type gormLogger struct {
+	logger        *zap.Logger
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// newGormLogger 创建 GORM 日志，slowThreshold <= 0 时不记录慢查询。
+func newGormLogger(zl *zap.Logger, level logger.LogLevel, slowThreshold time.Duration) *gormLogger {
+	if zl == nil {
+		zl = zap.NewNop()
+	}
+	return &gormLogger{
+		logger:        zl.WithOptions(zap.AddCallerSkip(3)),
+		level:         level,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// parseLogLevel 解析日志级别配置：silent、error、warn、info，空值返回默认级别。
+func parseLogLevel(s string) (logger.LogLevel, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return defaultLogLevel, nil
+	case "silent":
+		return logger.Silent, nil
+	case "error":
+		return logger.Error, nil
+	case "warn":
+		return logger.Warn, nil
+	case "info":
+		return logger.Info, nil
+	default:
+		return 0, fmt.Errorf("invalid gorm log level %q", s)
+	}
+}
+
+// LogMode 返回指定日志级别的新实例。
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	nl := *l
+	nl.level = level
+	return &nl
+}
+
+// Info 记录 info 日志。
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		l.logger.Info(fmt.Sprintf(msg, data...), l.traceField(ctx))
+	}
+}
+
+// Warn 记录 warn 日志。
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		l.logger.Warn(fmt.Sprintf(msg, data...), l.traceField(ctx))
+	}
+}
+
+// Error 记录 error 日志。
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		l.logger.Error(fmt.Sprintf(msg, data...), l.traceField(ctx))
+	}
+}
+
+// Trace 记录 SQL 执行日志：错误记为 error，超过慢查询阈值记为 warn，其余在 info 级别下记录。
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.logger.Error("gorm query error", l.queryFields(ctx, sql, rows, elapsed, zap.Error(err))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		l.logger.Warn("gorm slow query", l.queryFields(ctx, sql, rows, elapsed, zap.Duration("threshold", l.slowThreshold))...)
+	case l.level >= logger.Info:
+		sql, rows := fc()
+		l.logger.Info("gorm query", l.queryFields(ctx, sql, rows, elapsed)...)
+	}
+}
+
+func (l *gormLogger) queryFields(ctx context.Context, sql string, rows int64, elapsed time.Duration, extra ...zap.Field) []zap.Field {
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+		l.traceField(ctx),
+	}
+	return append(fields, extra...)
+}
+
+func (l *gormLogger) traceField(ctx context.Context) zap.Field {
+	if ctx != nil {
+		if traceID, ok := ctx.Value(traceIDKey).(string); ok && traceID != "" {
+			return zap.String("trace_id", traceID)
+		}
+	}
+	return zap.Skip()
+}
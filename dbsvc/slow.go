@@ -0,0 +1,121 @@
+package dbsvc
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// slowQueryPluginName 慢查询插件名称，同时作为回调名称前缀。
+const slowQueryPluginName = "dbsvc:slow_query"
+
+// slowQueryStartKey 语句实例中保存开始时间的键。
+const slowQueryStartKey = slowQueryPluginName + ":start"
+
+// SlowQueryInfo 慢查询信息。
+type SlowQueryInfo struct {
+	// SQL 执行的 SQL 语句（包含占位符）
+	SQL string
+	// Vars SQL 参数
+	Vars []any
+	// Duration 执行耗时
+	Duration time.Duration
+	// Threshold 触发时的慢查询阈值
+	Threshold time.Duration
+	// Rows 影响或返回的行数
+	Rows int64
+	// Err 执行错误，成功时为 nil
+	Err error
+}
+
+// OnSlowQuery 注册慢查询回调，执行耗时超过 slow_threshold（默认 200ms）的语句会调用 handler，
+// 可用于上报 APM 等场景。slow_threshold <= 0 的数据库不会触发回调。
+//
+// 可在 Boot 前后任意时刻注册，多次调用按注册顺序依次执行；handler 在执行 SQL 的协程中同步调用，
+// 耗时操作应自行异步处理。
+func (s *DbService) OnSlowQuery(handler func(info SlowQueryInfo)) {
+	if handler == nil {
+		return
+	}
+	s.slowMu.Lock()
+	defer s.slowMu.Unlock()
+	s.slowHandlers = append(s.slowHandlers, handler)
+}
+
+// slowQueryHandlers 返回已注册的慢查询回调。
+func (s *DbService) slowQueryHandlers() []func(info SlowQueryInfo) {
+	s.slowMu.RLock()
+	defer s.slowMu.RUnlock()
+	return s.slowHandlers
+}
+
+// 编译时检查，确保 slowQueryPlugin 实现了 gorm.Plugin 接口。
+var _ gorm.Plugin = (*slowQueryPlugin)(nil)
+
+// slowQueryPlugin 通过 gorm 回调统计语句耗时，超过阈值时调用 DbService 上注册的回调。
+type slowQueryPlugin struct {
+	service   *DbService
+	threshold time.Duration
+}
+
+// Name 返回插件名称。
+func (p *slowQueryPlugin) Name() string {
+	return slowQueryPluginName
+}
+
+// Initialize 在所有语句类型的回调链首尾注册计时回调。
+func (p *slowQueryPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	before, after := slowQueryPluginName+":before", slowQueryPluginName+":after"
+	errs := []error{
+		callback.Create().Before("*").Register(before, p.before),
+		callback.Create().After("*").Register(after, p.after),
+		callback.Query().Before("*").Register(before, p.before),
+		callback.Query().After("*").Register(after, p.after),
+		callback.Update().Before("*").Register(before, p.before),
+		callback.Update().After("*").Register(after, p.after),
+		callback.Delete().Before("*").Register(before, p.before),
+		callback.Delete().After("*").Register(after, p.after),
+		callback.Row().Before("*").Register(before, p.before),
+		callback.Row().After("*").Register(after, p.after),
+		callback.Raw().Before("*").Register(before, p.before),
+		callback.Raw().After("*").Register(after, p.after),
+	}
+	return errors.Join(errs...)
+}
+
+func (p *slowQueryPlugin) before(db *gorm.DB) {
+	db.InstanceSet(slowQueryStartKey, time.Now())
+}
+
+func (p *slowQueryPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(slowQueryStartKey)
+	if !ok {
+		return
+	}
+	begin, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(begin)
+	if elapsed <= p.threshold {
+		return
+	}
+
+	handlers := p.service.slowQueryHandlers()
+	if len(handlers) == 0 {
+		return
+	}
+	info := SlowQueryInfo{
+		SQL:       db.Statement.SQL.String(),
+		Vars:      db.Statement.Vars,
+		Duration:  elapsed,
+		Threshold: p.threshold,
+		Rows:      db.Statement.RowsAffected,
+		Err:       db.Error,
+	}
+	for _, handler := range handlers {
+		handler(info)
+	}
+}
@@ -0,0 +1,89 @@
+package dbsvc
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDbService_OnSlowQuery(t *testing.T) {
+	setup := func(t *testing.T, threshold string) (*DbService, context.Context) {
+		t.Helper()
+		svc := NewDbService()
+		ctx := createTestContext(t, Name, map[string]interface{}{
+			"public.common.driver_type":    "sqlite",
+			"public.common.dsn":            filepath.Join(t.TempDir(), "slow.db"),
+			"public.common.log_level":      "silent",
+			"public.common.slow_threshold": threshold,
+		})
+		require.NoError(t, svc.Boot(ctx))
+		t.Cleanup(func() { _ = svc.Close(context.Background()) })
+		return svc, ctx
+	}
+
+	t.Run("超过阈值触发回调", func(t *testing.T) {
+		svc, ctx := setup(t, "1ns")
+
+		var (
+			mu    sync.Mutex
+			infos []SlowQueryInfo
+		)
+		svc.OnSlowQuery(func(info SlowQueryInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			infos = append(infos, info)
+		})
+
+		db := svc.MustDB(ctx, "public", "common")
+		require.NoError(t, db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)").Error)
+		require.NoError(t, db.Exec("INSERT INTO users (name) VALUES (?), (?)", "a", "b").Error)
+
+		var names []string
+		require.NoError(t, db.Raw("SELECT name FROM users WHERE id > ?", 0).Scan(&names).Error)
+		assert.Equal(t, []string{"a", "b"}, names)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, infos, 3)
+
+		insert := infos[1]
+		assert.Equal(t, "INSERT INTO users (name) VALUES (?), (?)", insert.SQL)
+		assert.Equal(t, []any{"a", "b"}, insert.Vars)
+		assert.Equal(t, int64(2), insert.Rows)
+		assert.NoError(t, insert.Err)
+
+		query := infos[2]
+		assert.Equal(t, "SELECT name FROM users WHERE id > ?", query.SQL)
+		assert.Equal(t, []any{0}, query.Vars)
+		assert.Greater(t, query.Duration, time.Duration(0))
+		assert.Equal(t, time.Nanosecond, query.Threshold)
+	})
+
+	t.Run("未超过阈值不触发", func(t *testing.T) {
+		svc, ctx := setup(t, "1h")
+
+		called := false
+		svc.OnSlowQuery(func(info SlowQueryInfo) { called = true })
+
+		var n int
+		require.NoError(t, svc.MustDB(ctx, "public", "common").Raw("SELECT 1").Scan(&n).Error)
+		assert.Equal(t, 1, n)
+		assert.False(t, called)
+	})
+
+	t.Run("阈值为 0 时关闭", func(t *testing.T) {
+		svc, ctx := setup(t, "0")
+
+		called := false
+		svc.OnSlowQuery(func(info SlowQueryInfo) { called = true })
+
+		var n int
+		require.NoError(t, svc.MustDB(ctx, "public", "common").Raw("SELECT 1").Scan(&n).Error)
+		assert.False(t, called)
+	})
+}
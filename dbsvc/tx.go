@@ -0,0 +1,81 @@
+package dbsvc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// txKey 上下文中保存事务的键，按分组与数据库区分。
+type txKey struct {
+	group string
+	name  string
+}
+
+// txFromContext 返回上下文中指定数据库的进行中事务。
+func txFromContext(ctx context.Context, group, name string) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{group: group, name: name}).(*gorm.DB)
+	return tx, ok && tx != nil
+}
+
+// Transaction 在指定数据库上执行事务。
+//
+// fn 返回 error 或 panic 时回滚，否则提交；回滚失败会记录日志。
+// 回调中的 tx 已绑定包含该事务的上下文，使用 tx.Statement.Context 再次调用
+// Transaction 时会复用外层事务并通过 SavePoint 实现嵌套。
+// ctx 已取消时不会开启事务；fn 执行期间 ctx 被取消时回滚并返回 ctx.Err()。
+func (s *DbService) Transaction(ctx context.Context, group, name string, fn func(tx *gorm.DB) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if tx, ok := txFromContext(ctx, group, name); ok {
+		return tx.WithContext(ctx).Transaction(fn)
+	}
+
+	db, err := s.DB(ctx, group, name)
+	if err != nil {
+		return err
+	}
+
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("begin transaction: %w", tx.Error)
+	}
+	tx = tx.WithContext(context.WithValue(ctx, txKey{group: group, name: name}, tx))
+
+	committed := false
+	defer func() {
+		if !committed {
+			s.rollback(tx, group, name)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// rollback 回滚事务并记录回滚失败。
+func (s *DbService) rollback(tx *gorm.DB, group, name string) {
+	// ctx 取消时 database/sql 会自动回滚，此时返回 sql.ErrTxDone，无需记录
+	if err := tx.Rollback().Error; err != nil && !errors.Is(err, sql.ErrTxDone) && s.logger != nil {
+		s.logger.Error("failed to rollback transaction",
+			zap.String("group", group),
+			zap.String("db", name),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,116 @@
+package dbsvc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupTxTestService 启动使用 sqlite 文件数据库的服务并创建测试表。
+func setupTxTestService(t *testing.T) (*DbService, context.Context) {
+	t.Helper()
+	svc := NewDbService()
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"public.common.driver_type": "sqlite",
+		"public.common.dsn":         filepath.Join(t.TempDir(), "tx.db"),
+	})
+	require.NoError(t, svc.Boot(ctx))
+	t.Cleanup(func() { _ = svc.Close(context.Background()) })
+
+	db := svc.MustDB(ctx, "public", "common")
+	require.NoError(t, db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)").Error)
+	return svc, ctx
+}
+
+func countUsers(t *testing.T, svc *DbService, ctx context.Context) int64 {
+	t.Helper()
+	var n int64
+	require.NoError(t, svc.MustDB(ctx, "public", "common").Table("users").Count(&n).Error)
+	return n
+}
+
+func TestDbService_Transaction(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		svc, ctx := setupTxTestService(t)
+		err := svc.Transaction(ctx, "public", "common", func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO users (name) VALUES (?)", "alice").Error
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), countUsers(t, svc, ctx))
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		svc, ctx := setupTxTestService(t)
+		wantErr := errors.New("boom")
+		err := svc.Transaction(ctx, "public", "common", func(tx *gorm.DB) error {
+			require.NoError(t, tx.Exec("INSERT INTO users (name) VALUES (?)", "alice").Error)
+			return wantErr
+		})
+		assert.ErrorIs(t, err, wantErr)
+		assert.Equal(t, int64(0), countUsers(t, svc, ctx))
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		svc, ctx := setupTxTestService(t)
+		assert.Panics(t, func() {
+			_ = svc.Transaction(ctx, "public", "common", func(tx *gorm.DB) error {
+				require.NoError(t, tx.Exec("INSERT INTO users (name) VALUES (?)", "alice").Error)
+				panic("boom")
+			})
+		})
+		assert.Equal(t, int64(0), countUsers(t, svc, ctx))
+	})
+
+	t.Run("nested rollback", func(t *testing.T) {
+		svc, ctx := setupTxTestService(t)
+		err := svc.Transaction(ctx, "public", "common", func(tx *gorm.DB) error {
+			require.NoError(t, tx.Exec("INSERT INTO users (name) VALUES (?)", "alice").Error)
+
+			nestedErr := svc.Transaction(tx.Statement.Context, "public", "common", func(tx *gorm.DB) error {
+				require.NoError(t, tx.Exec("INSERT INTO users (name) VALUES (?)", "bob").Error)
+				return errors.New("nested failed")
+			})
+			assert.Error(t, nestedErr)
+			return nil
+		})
+		require.NoError(t, err)
+
+		var names []string
+		require.NoError(t, svc.MustDB(ctx, "public", "common").Table("users").Pluck("name", &names).Error)
+		assert.Equal(t, []string{"alice"}, names)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		svc, ctx := setupTxTestService(t)
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		called := false
+		err := svc.Transaction(canceled, "public", "common", func(tx *gorm.DB) error {
+			called = true
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, called)
+
+		txCtx, cancel := context.WithCancel(ctx)
+		err = svc.Transaction(txCtx, "public", "common", func(tx *gorm.DB) error {
+			require.NoError(t, tx.Exec("INSERT INTO users (name) VALUES (?)", "alice").Error)
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int64(0), countUsers(t, svc, ctx))
+	})
+
+	t.Run("db not found", func(t *testing.T) {
+		svc, ctx := setupTxTestService(t)
+		err := svc.Transaction(ctx, "public", "missing", func(tx *gorm.DB) error { return nil })
+		assert.Error(t, err)
+	})
+}
@@ -1,6 +1,15 @@
 package ginsrv
 
-import "time"
+import (
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// withYamlTag 让 viper 按 yaml 标签解析配置，使 cert_file、force_ssl 等多单词字段生效
+func withYamlTag(dc *mapstructure.DecoderConfig) {
+	dc.TagName = "yaml"
+}
 
 type Config struct {
 	Mode            string        `yaml:"mode"`             // debug, release, test
@@ -20,4 +29,40 @@ type Config struct {
 		KeyFile  string `yaml:"key_file"`
 		ForceSsl bool   `yaml:"force_ssl"`
 	} `yaml:"https"`
+	AccessLog AccessLogConfig `yaml:"access_log"`
+	Health    HealthConfig    `yaml:"health"`
+	Trace     TraceConfig     `yaml:"trace"`
+	Pprof     PprofConfig     `yaml:"pprof"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Static    StaticConfig    `yaml:"static"`
+}
+
+// AccessLogConfig 访问日志配置
+type AccessLogConfig struct {
+	// MaxBodySize 记录请求、响应 body 的最大字节数，未配置时默认 4KB，0 表示不记录 body
+	MaxBodySize *int `yaml:"max_body_size"`
+	// MaskFields 需要脱敏的 JSON 字段，如 password、token
+	MaskFields []string `yaml:"mask_fields"`
+}
+
+// BodySize 返回生效的 body 记录大小
+func (c AccessLogConfig) BodySize() int {
+	if c.MaxBodySize == nil {
+		return maxBodySize
+	}
+	return *c.MaxBodySize
+}
+
+// TraceConfig 链路追踪配置
+type TraceConfig struct {
+	// Header 读取、写回 trace id 的请求头，默认 X-Request-ID
+	Header string `yaml:"header"`
+}
+
+// HeaderName 返回生效的 trace 请求头
+func (c TraceConfig) HeaderName() string {
+	if c.Header == "" {
+		return DefaultTraceHeader
+	}
+	return c.Header
 }
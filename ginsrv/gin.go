@@ -5,11 +5,19 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/qq1060656096/drugo/drugo"
 	"github.com/qq1060656096/drugo/kernel"
 	"go.uber.org/zap"
@@ -28,6 +36,40 @@ type GinService struct {
 	httpServer *http.Server
 	tlsServer  *http.Server
 	once       sync.Once
+
+	// httpAddr、httpsAddr 服务器实际监听的地址，Run 创建监听后设置
+	addrMu    sync.RWMutex
+	httpAddr  net.Addr
+	httpsAddr net.Addr
+
+	// signalHandling 为 true 时 Run 监听 SIGINT/SIGTERM 并优雅关闭
+	signalHandling bool
+	// notifySignal 注册信号监听，默认为 signal.Notify，测试时可替换
+	notifySignal func(c chan<- os.Signal, sig ...os.Signal)
+
+	// readiness 就绪检查，healthOnce 保证健康检查路由只注册一次
+	readiness  readinessChecks
+	healthOnce sync.Once
+	// pprofOnce 保证 pprof 路由只注册一次
+	pprofOnce sync.Once
+	// metricsOnce 保证 metrics 路由只注册一次，metricsGatherer 为 metrics 路由输出的指标来源
+	metricsOnce     sync.Once
+	metricsGatherer prometheus.Gatherer
+	// staticOnce 保证静态文件处理只注册一次
+	staticOnce sync.Once
+
+	// closeOnce 保证关闭流程只执行一次，closeErr 保存首次关闭的结果
+	closeOnce sync.Once
+	closeErr  error
+
+	// onStart、onShutdown 生命周期钩子
+	hooksMu    sync.Mutex
+	onStart    []Hook
+	onShutdown []Hook
+
+	// traceHandler 按配置 trace.header 创建的追踪中间件，首个请求时创建
+	traceHandler gin.HandlerFunc
+	traceOnce    sync.Once
 }
 
 // Name 实现 kernel.Service 接口
@@ -45,7 +87,18 @@ func (s *GinService) Boot(ctx context.Context) error {
 }
 
 // Close 优雅关闭，注意：标准库风格中，Context 应透传给 Shutdown
+//
+// 只执行一次：启用信号处理时 Run 与 drugo 都可能调用 Close，
+// 后续调用不会重复执行关闭钩子，直接返回首次关闭的结果。
 func (s *GinService) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		s.closeErr = s.close(ctx)
+	})
+	return s.closeErr
+}
+
+// close 执行关闭钩子并关闭 HTTP/HTTPS 服务器
+func (s *GinService) close(ctx context.Context) error {
 	k := kernel.MustFromContext(ctx)
 	logger := k.Logger().MustGet(s.Name())
 	logger.Info("closing gin service")
@@ -58,6 +111,9 @@ func (s *GinService) Close(ctx context.Context) error {
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	// 关闭服务器前执行关闭钩子
+	s.runHooks(timeoutCtx, logger, "shutdown", s.shutdownHooks())
+
 	var errs []error
 	if s.httpServer != nil {
 		logger.Info("shutting down http server", zap.String("addr", s.httpServer.Addr))
@@ -97,7 +153,7 @@ func (s *GinService) Run(ctx context.Context) error {
 	// 1. 配置加载
 	logger.Debug("loading config")
 	confGetter := k.Config().MustGet(s.Name())
-	if err := confGetter.Unmarshal(s.config); err != nil {
+	if err := confGetter.Unmarshal(s.config, withYamlTag); err != nil {
 		logger.Error("failed to unmarshal config", zap.Error(err))
 		return fmt.Errorf("unmarshal config: %w", err)
 	}
@@ -116,7 +172,13 @@ func (s *GinService) Run(ctx context.Context) error {
 		logger.Info("gin mode set", zap.String("mode", s.config.Mode))
 	}
 
-	// 3. 获取超时配置，使用默认值
+	// 3. 注册健康检查、pprof、metrics 路由及静态文件处理
+	s.registerHealthRoutes()
+	s.registerPprofRoutes()
+	s.registerMetricsRoutes()
+	s.registerStaticRoutes()
+
+	// 4. 获取超时配置，使用默认值
 	readTimeout := s.config.ReadTimeout
 	if readTimeout <= 0 {
 		readTimeout = 15 * time.Second
@@ -132,11 +194,24 @@ func (s *GinService) Run(ctx context.Context) error {
 
 	errChan := make(chan error, 2)
 
-	// 4. HTTP Server 启动
+	// 在启动服务前注册信号监听，避免启动期间收到的信号被默认处理
+	var sigChan chan os.Signal
+	if s.signalHandling {
+		sigChan = make(chan os.Signal, 1)
+		notify := s.notifySignal
+		if notify == nil {
+			notify = signal.Notify
+		}
+		notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
+		logger.Info("signal handling enabled", zap.Strings("signals", []string{"SIGINT", "SIGTERM"}))
+	}
+
+	// 5. HTTP Server 启动
 	if s.config.Http.Enabled {
 		s.httpServer = &http.Server{
 			Addr:         fmt.Sprintf("%s:%d", s.config.Host, s.config.Http.Port),
-			Handler:      s.engine,
+			Handler:      s.httpHandler(),
 			ReadTimeout:  readTimeout,
 			WriteTimeout: writeTimeout,
 			IdleTimeout:  idleTimeout,
@@ -149,12 +224,21 @@ func (s *GinService) Run(ctx context.Context) error {
 			zap.String("url", url),
 			zap.String("addr", s.httpServer.Addr),
 			zap.String("protocol", "http"),
+			zap.Bool("force_ssl", s.forceSsl()),
 			zap.Duration("read_timeout", readTimeout),
 			zap.Duration("write_timeout", writeTimeout),
 			zap.Duration("idle_timeout", idleTimeout),
 		)
+		// 同步监听端口，确保 OnStart 钩子执行时已可接受连接
+		ln, err := net.Listen("tcp", s.httpServer.Addr)
+		if err != nil {
+			logger.Error("http server listen failed", zap.String("addr", s.httpServer.Addr), zap.Error(err))
+			return fmt.Errorf("http listen: %w", err)
+		}
+		s.setAddr(&s.httpAddr, ln.Addr())
+		logger.Info("http server listening", zap.String("addr", ln.Addr().String()))
 		go func() {
-			if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			if err := s.httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
 				logger.Error("http server error", zap.String("addr", s.httpServer.Addr), zap.Error(err))
 				errChan <- err
 			}
@@ -163,7 +247,7 @@ func (s *GinService) Run(ctx context.Context) error {
 		logger.Debug("http server disabled")
 	}
 
-	// 5. HTTPS Server 启动
+	// 6. HTTPS Server 启动
 	if s.config.Https.Enabled {
 		s.tlsServer = &http.Server{
 			Addr:         fmt.Sprintf("%s:%d", s.config.Host, s.config.Https.Port),
@@ -184,8 +268,18 @@ func (s *GinService) Run(ctx context.Context) error {
 			zap.Duration("write_timeout", writeTimeout),
 			zap.Duration("idle_timeout", idleTimeout),
 		)
+		ln, err := net.Listen("tcp", s.tlsServer.Addr)
+		if err != nil {
+			logger.Error("https server listen failed", zap.String("addr", s.tlsServer.Addr), zap.Error(err))
+			if s.httpServer != nil {
+				_ = s.httpServer.Close()
+			}
+			return fmt.Errorf("https listen: %w", err)
+		}
+		s.setAddr(&s.httpsAddr, ln.Addr())
+		logger.Info("https server listening", zap.String("addr", ln.Addr().String()))
 		go func() {
-			if err := s.tlsServer.ListenAndServeTLS(s.config.Https.CertFile, s.config.Https.KeyFile); err != nil && err != http.ErrServerClosed {
+			if err := s.tlsServer.ServeTLS(ln, s.config.Https.CertFile, s.config.Https.KeyFile); err != nil && err != http.ErrServerClosed {
 				logger.Error("https server error",
 					zap.String("addr", s.tlsServer.Addr),
 					zap.String("cert_file", s.config.Https.CertFile),
@@ -200,23 +294,130 @@ func (s *GinService) Run(ctx context.Context) error {
 
 	logger.Info("gin service running")
 
-	// 6. 阻塞等待
+	// 7. 执行启动钩子
+	s.runHooks(ctx, logger, "start", s.startHooks())
+
+	// 8. 阻塞等待
 	select {
 	case <-ctx.Done():
 		logger.Info("gin service received stop signal", zap.Error(ctx.Err()))
 		return nil
+	case sig := <-sigChan:
+		logger.Info("gin service received os signal, shutting down", zap.String("signal", sig.String()))
+		if err := s.Close(ctx); err != nil {
+			return fmt.Errorf("graceful shutdown: %w", err)
+		}
+		return nil
 	case err := <-errChan:
 		logger.Error("gin service stopped due to server error", zap.Error(err))
 		return fmt.Errorf("server error: %w", err)
 	}
 }
 
+// HTTPAddr 返回 HTTP 服务器实际监听的地址，未启用或尚未开始监听时返回 nil。
+// 配置端口为 0 时可通过该方法获取系统分配的端口，OnStart 钩子中调用时已可获取。
+func (s *GinService) HTTPAddr() net.Addr {
+	s.addrMu.RLock()
+	defer s.addrMu.RUnlock()
+	return s.httpAddr
+}
+
+// HTTPSAddr 返回 HTTPS 服务器实际监听的地址，未启用或尚未开始监听时返回 nil。
+func (s *GinService) HTTPSAddr() net.Addr {
+	s.addrMu.RLock()
+	defer s.addrMu.RUnlock()
+	return s.httpsAddr
+}
+
+// setAddr 在锁保护下设置监听地址。
+func (s *GinService) setAddr(dst *net.Addr, addr net.Addr) {
+	s.addrMu.Lock()
+	defer s.addrMu.Unlock()
+	*dst = addr
+}
+
+// Group 创建带中间件的路由组，引擎未初始化时自动初始化
+//
+// 示例：
+//
+//	v1 := ginSvc.Group("/api/v1", authMiddleware)
+//	v1.GET("/users", listUsers)
+func (s *GinService) Group(prefix string, mw ...gin.HandlerFunc) *gin.RouterGroup {
+	s.init()
+	return s.engine.Group(prefix, mw...)
+}
+
+// forceSsl 返回是否将 HTTP 请求重定向到 HTTPS，需同时启用 HTTP、HTTPS 服务器
+func (s *GinService) forceSsl() bool {
+	return s.config.Http.Enabled && s.config.Https.Enabled && s.config.Https.ForceSsl
+}
+
+// httpHandler 返回 HTTP 服务器的处理器，开启 force_ssl 时重定向到 HTTPS
+func (s *GinService) httpHandler() http.Handler {
+	if s.forceSsl() {
+		return httpsRedirectHandler(s.config.Https.Port)
+	}
+	return s.engine
+}
+
+// httpsRedirectHandler 将请求重定向到相同主机、路径和查询参数的 HTTPS 地址。
+// GET、HEAD 使用 301，其余方法使用 308 以保留请求方法和 body。
+func httpsRedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 && httpsPort > 0 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		} else if strings.Contains(host, ":") {
+			// 默认端口的 IPv6 地址需要加方括号
+			host = "[" + host + "]"
+		}
+
+		target := url.URL{
+			Scheme:   "https",
+			Host:     host,
+			Path:     r.URL.Path,
+			RawPath:  r.URL.RawPath,
+			RawQuery: r.URL.RawQuery,
+		}
+
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, target.String(), code)
+	})
+}
+
 // Engine 获取 Gin 引擎实例
 func (s *GinService) Engine() *gin.Engine {
 	s.init()
 	return s.engine
 }
 
+// AccessLogger 返回使用服务配置 access_log、trace.header 的访问日志中间件。
+// 配置在 Run 时加载，中间件在每个请求处理时读取生效的 body 记录大小。
+func (s *GinService) AccessLogger(lmg interface{ MustGet(string) *zap.Logger }, accessLogName string, errLogName string) gin.HandlerFunc {
+	s.init()
+	return AccessLogger(lmg, accessLogName, errLogName, func(o *accessLogOptions) {
+		// 配置在 Run 时加载，需在每次请求时读取，不能绑定注册时的配置副本
+		o.maxBodySize = func() int { return s.config.AccessLog.BodySize() }
+		o.traceHeader = func() string { return s.config.Trace.HeaderName() }
+		o.maskFields = func() []string { return s.config.AccessLog.MaskFields }
+	})
+}
+
+// traceMiddleware 使用配置 trace.header 的链路追踪中间件。
+// 配置在 Run 时加载，因此在首个请求时才创建 TraceMiddleware。
+func (s *GinService) traceMiddleware(c *gin.Context) {
+	s.traceOnce.Do(func() {
+		s.traceHandler = TraceMiddleware(s.config.Trace.HeaderName())
+	})
+	s.traceHandler(c)
+}
+
 // SetEngineContextAppVar 设置 gin app变量
 func (s *GinService) SetEngineContextAppVar(app kernel.Kernel) {
 	s.init()
@@ -231,6 +432,8 @@ func (s *GinService) init() {
 	s.once.Do(func() {
 		s.config = &Config{}
 		s.engine = gin.New()
+		// 自动注册链路追踪，需在所有路由之前
+		s.engine.Use(s.traceMiddleware)
 		// 默认 Ping 路由放在初始化里
 		s.engine.GET("/ping", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "pong"})
@@ -251,3 +454,19 @@ func New(opts ...Option) *GinService {
 func WithName(name string) Option {
 	return func(s *GinService) { s.name = name }
 }
+
+// WithMetricsGatherer 设置 metrics 路由输出的指标来源，默认 prometheus.DefaultGatherer。
+// 使用自定义注册器时与 WithMetricsRegisterer 传入同一个 *prometheus.Registry：
+//
+//	reg := prometheus.NewRegistry()
+//	ginSvc := ginsrv.New(ginsrv.WithMetricsGatherer(reg))
+//	engine.Use(ginsrv.Metrics(ginsrv.WithMetricsRegisterer(reg)))
+func WithMetricsGatherer(gatherer prometheus.Gatherer) Option {
+	return func(s *GinService) { s.metricsGatherer = gatherer }
+}
+
+// WithSignalHandling 设置 Run 是否监听 SIGINT/SIGTERM。
+// 启用后收到信号时调用 Close 优雅关闭服务器并返回 nil。
+func WithSignalHandling(enabled bool) Option {
+	return func(s *GinService) { s.signalHandling = enabled }
+}
@@ -3,19 +3,25 @@ package ginsrv
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/qq1060656096/drugo/config"
 	"github.com/qq1060656096/drugo/kernel"
 	"github.com/qq1060656096/drugo/log"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // mockKernel 模拟 kernel 接口
@@ -92,6 +98,7 @@ func createMockKernel(t *testing.T, serviceName string, ginConfig *Config) *mock
 	v.Set(serviceName+".https.cert_file", ginConfig.Https.CertFile)
 	v.Set(serviceName+".https.key_file", ginConfig.Https.KeyFile)
 	v.Set(serviceName+".https.force_ssl", ginConfig.Https.ForceSsl)
+	v.Set(serviceName+".trace.header", ginConfig.Trace.Header)
 
 	// 写入配置文件
 	err = v.WriteConfigAs(configFile)
@@ -184,6 +191,58 @@ func TestGinService_Engine(t *testing.T) {
 	assert.Equal(t, "pong", response["message"])
 }
 
+// TestGinService_Group 测试路由组注册
+func TestGinService_Group(t *testing.T) {
+	service := New()
+
+	var calls []string
+	auth := func(c *gin.Context) {
+		calls = append(calls, "auth")
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+
+	// 引擎未初始化时自动初始化
+	v1 := service.Group("/api/v1", auth)
+	require.NotNil(t, v1)
+	v1.GET("/users", func(c *gin.Context) {
+		calls = append(calls, "handler")
+		c.String(http.StatusOK, "users")
+	})
+	service.Group("/api/v2").GET("/users", func(c *gin.Context) {
+		c.String(http.StatusOK, "users v2")
+	})
+
+	do := func(path string, authorized bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authorized {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		w := httptest.NewRecorder()
+		service.Engine().ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/api/v1/users", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "users", w.Body.String())
+	assert.Equal(t, []string{"auth", "handler"}, calls)
+
+	calls = nil
+	assert.Equal(t, http.StatusUnauthorized, do("/api/v1/users", false).Code)
+	assert.Equal(t, []string{"auth"}, calls)
+
+	// 其他分组不受中间件影响
+	calls = nil
+	w = do("/api/v2/users", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "users v2", w.Body.String())
+	assert.Empty(t, calls)
+}
+
 // TestGinService_init 测试 init 方法
 func TestGinService_init(t *testing.T) {
 	service := New()
@@ -434,6 +493,142 @@ func TestGinService_Run_ContextCancellation(t *testing.T) {
 	}
 }
 
+// TestGinService_Run_Addr 测试随机端口启动后可获取实际监听地址
+func TestGinService_Run_Addr(t *testing.T) {
+	service := New(WithName("test-addr"))
+	config := &Config{
+		Mode: "test",
+		Host: "127.0.0.1",
+		Http: struct {
+			Enabled bool `yaml:"enabled"`
+			Port    int  `yaml:"port"`
+		}{
+			Enabled: true,
+			Port:    0, // 随机端口
+		},
+	}
+
+	ctx := createTestContext(t, "test-addr", config)
+	require.NoError(t, service.Boot(ctx))
+	assert.Nil(t, service.HTTPAddr())
+
+	service.Engine().GET("/addr", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	started := make(chan net.Addr, 1)
+	service.OnStart(func(ctx context.Context) error {
+		started <- service.HTTPAddr()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- service.Run(ctx)
+	}()
+
+	var addr net.Addr
+	select {
+	case addr = <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务未在预期时间内启动")
+	}
+	require.NotNil(t, addr)
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	require.True(t, ok)
+	assert.NotZero(t, tcpAddr.Port)
+	assert.Equal(t, addr, service.HTTPAddr())
+	assert.Nil(t, service.HTTPSAddr())
+
+	resp, err := http.Get("http://" + addr.String() + "/addr")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	select {
+	case err := <-errChan:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务未在预期时间内停止")
+	}
+}
+
+// TestGinService_Run_SignalHandling 测试收到系统信号时优雅关闭
+func TestGinService_Run_SignalHandling(t *testing.T) {
+	service := New(WithName("test-signal"), WithSignalHandling(true))
+	registered := make(chan chan<- os.Signal, 1)
+	service.notifySignal = func(c chan<- os.Signal, sig ...os.Signal) {
+		assert.ElementsMatch(t, []os.Signal{syscall.SIGINT, syscall.SIGTERM}, sig)
+		registered <- c
+	}
+
+	config := &Config{
+		Mode: "test",
+		Host: "localhost",
+		Http: struct {
+			Enabled bool `yaml:"enabled"`
+			Port    int  `yaml:"port"`
+		}{
+			Enabled: true,
+			Port:    0,
+		},
+	}
+	ctx := createTestContext(t, "test-signal", config)
+	require.NoError(t, service.Boot(ctx))
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- service.Run(ctx)
+	}()
+
+	var sigChan chan<- os.Signal
+	select {
+	case sigChan = <-registered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("信号监听未注册")
+	}
+	sigChan <- syscall.SIGTERM
+
+	select {
+	case err := <-errChan:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务未在预期时间内停止")
+	}
+
+	// 服务器已关闭，不再接受请求
+	require.NotNil(t, service.httpServer)
+	assert.ErrorIs(t, service.httpServer.ListenAndServe(), http.ErrServerClosed)
+}
+
+// TestGinService_Run_SignalHandlingDisabled 测试默认不注册信号监听
+func TestGinService_Run_SignalHandlingDisabled(t *testing.T) {
+	service := New(WithName("test-no-signal"))
+	service.notifySignal = func(c chan<- os.Signal, sig ...os.Signal) {
+		t.Error("不应注册信号监听")
+	}
+
+	config := &Config{
+		Mode: "test",
+		Host: "localhost",
+		Http: struct {
+			Enabled bool `yaml:"enabled"`
+			Port    int  `yaml:"port"`
+		}{
+			Enabled: true,
+			Port:    0,
+		},
+	}
+	ctx := createTestContext(t, "test-no-signal", config)
+	require.NoError(t, service.Boot(ctx))
+
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, service.Run(ctx))
+}
+
 // TestGinService_Run_DefaultTimeouts 测试默认超时值
 func TestGinService_Run_DefaultTimeouts(t *testing.T) {
 	service := New(WithName("test-timeouts"))
@@ -624,3 +819,179 @@ func TestGinService_Integration(t *testing.T) {
 	err = service.Close(closeCtx)
 	assert.NoError(t, err)
 }
+
+// TestGinService_ForceSsl 测试开启 force_ssl 时 HTTP 请求重定向到 HTTPS
+func TestGinService_ForceSsl(t *testing.T) {
+	newConfig := func(forceSsl bool) *Config {
+		cfg := &Config{Mode: "test", Host: "localhost"}
+		cfg.Http.Enabled = true
+		cfg.Http.Port = 18001
+		cfg.Https.Enabled = true
+		cfg.Https.Port = 18443
+		cfg.Https.ForceSsl = forceSsl
+		return cfg
+	}
+	boot := func(t *testing.T, cfg *Config) *GinService {
+		service := New(WithName("test-ssl"))
+		ctx := createTestContext(t, "test-ssl", cfg)
+		require.NoError(t, service.Boot(ctx))
+		require.NoError(t, kernel.MustFromContext(ctx).Config().MustGet("test-ssl").Unmarshal(service.config, withYamlTag))
+		service.Engine().GET("/users", func(c *gin.Context) {
+			c.String(http.StatusOK, "users")
+		})
+		return service
+	}
+
+	t.Run("重定向到HTTPS", func(t *testing.T) {
+		service := boot(t, newConfig(true))
+
+		tests := []struct {
+			method   string
+			target   string
+			wantCode int
+			wantURL  string
+		}{
+			{http.MethodGet, "http://localhost:18001/users?page=2&size=10", http.StatusMovedPermanently, "https://localhost:18443/users?page=2&size=10"},
+			{http.MethodGet, "http://example.com/users", http.StatusMovedPermanently, "https://example.com:18443/users"},
+			{http.MethodPost, "http://localhost:18001/users", http.StatusPermanentRedirect, "https://localhost:18443/users"},
+		}
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			service.httpHandler().ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+			assert.Equal(t, tt.wantCode, w.Code, tt.target)
+			assert.Equal(t, tt.wantURL, w.Header().Get("Location"), tt.target)
+		}
+	})
+
+	t.Run("未开启时正常处理", func(t *testing.T) {
+		service := boot(t, newConfig(false))
+
+		w := httptest.NewRecorder()
+		service.httpHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:18001/users", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "users", w.Body.String())
+	})
+}
+
+// TestHttpsRedirectHandler_DefaultPort 测试 443 端口时省略端口号
+func TestHttpsRedirectHandler_DefaultPort(t *testing.T) {
+	w := httptest.NewRecorder()
+	httpsRedirectHandler(443).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example.com:8080/a/b?x=1", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "https://example.com/a/b?x=1", w.Header().Get("Location"))
+}
+
+// TestGinService_TraceHeader 测试按配置 trace.header 自动注册链路追踪
+func TestGinService_TraceHeader(t *testing.T) {
+	cfg := &Config{Mode: "test"}
+	cfg.Trace.Header = "X-Trace-Id"
+
+	service := New(WithName("test-trace"))
+	ctx := createTestContext(t, "test-trace", cfg)
+	require.NoError(t, service.Boot(ctx))
+
+	// 与实际使用一致：先注册中间件和路由，Run 时再加载配置
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	engine := service.Engine()
+	engine.Use(service.AccessLogger(&mockLogManager{accessLogger: logger, errorLogger: logger}, "gin.access", "gin.error"))
+	engine.GET("/trace", func(c *gin.Context) {
+		c.String(http.StatusOK, GetTraceID(c))
+	})
+	require.NoError(t, kernel.MustFromContext(ctx).Config().MustGet("test-trace").Unmarshal(service.config, withYamlTag))
+
+	t.Run("使用入站trace id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+		req.Header.Set("X-Trace-Id", "inbound-trace-id")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		assert.Equal(t, "inbound-trace-id", w.Body.String())
+		assert.Equal(t, "inbound-trace-id", w.Header().Get("X-Trace-Id"))
+		assert.Empty(t, w.Header().Get(DefaultTraceHeader))
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "inbound-trace-id", entries[0].ContextMap()["trace_id"])
+	})
+
+	t.Run("生成trace id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+		// 默认请求头不再生效
+		req.Header.Set(DefaultTraceHeader, "ignored")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		traceID := w.Header().Get("X-Trace-Id")
+		assert.NotEmpty(t, traceID)
+		assert.NotEqual(t, "ignored", traceID)
+		assert.Equal(t, traceID, w.Body.String())
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, traceID, entries[0].ContextMap()["trace_id"])
+	})
+}
+
+// TestGinService_AccessLogger_TraceHeaderLoadedAfterRegister 测试未注册链路追踪的路由上，
+// 访问日志按 Run 时加载的 trace.header 从请求头读取 trace id
+func TestGinService_AccessLogger_TraceHeaderLoadedAfterRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := New()
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	router := gin.New()
+	router.Use(service.AccessLogger(&mockLogManager{accessLogger: logger, errorLogger: logger}, "gin.access", "gin.error"))
+	router.GET("/trace", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	v := viper.New()
+	v.Set("trace.header", "X-Trace-Id")
+	require.NoError(t, v.Unmarshal(service.config, withYamlTag))
+
+	req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+	req.Header.Set("X-Trace-Id", "inbound-trace-id")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "inbound-trace-id", entries[0].ContextMap()["trace_id"])
+}
+
+// TestGinService_TraceHeader_Default 测试未配置时使用 X-Request-ID
+func TestGinService_TraceHeader_Default(t *testing.T) {
+	service := New()
+	service.init()
+	service.Engine().GET("/trace", func(c *gin.Context) {
+		c.String(http.StatusOK, GetTraceID(c))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+	req.Header.Set(DefaultTraceHeader, "request-id")
+	w := httptest.NewRecorder()
+	service.Engine().ServeHTTP(w, req)
+
+	assert.Equal(t, "request-id", w.Body.String())
+	assert.Equal(t, "request-id", w.Header().Get(DefaultTraceHeader))
+}
+
+func TestGinService_TraceMiddleware_MultipleHeaders(t *testing.T) {
+	service := New()
+	service.init()
+	// GinService 自动注册的追踪中间件只读取 trace.header，业务追加的多请求头中间件仍然生效
+	engine := service.Engine()
+	engine.Use(TraceMiddleware(DefaultTraceHeader, "X-Trace-Id"))
+	engine.GET("/trace", func(c *gin.Context) {
+		c.String(http.StatusOK, GetTraceID(c))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+	req.Header.Set("X-Trace-Id", "trace-id")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, "trace-id", w.Body.String())
+	assert.Equal(t, "trace-id", w.Header().Get(DefaultTraceHeader))
+}
@@ -0,0 +1,198 @@
+package ginsrv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 健康检查默认配置
+const (
+	defaultLivenessPath  = "/healthz"
+	defaultReadinessPath = "/readyz"
+	defaultHealthTimeout = 5 * time.Second
+)
+
+// 健康检查状态
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	// Enabled 是否注册健康检查路由，默认 true
+	Enabled *bool `yaml:"enabled"`
+	// LivenessPath 存活检查路径，默认 /healthz
+	LivenessPath string `yaml:"liveness_path"`
+	// ReadinessPath 就绪检查路径，默认 /readyz
+	ReadinessPath string `yaml:"readiness_path"`
+	// Timeout 就绪检查超时时间，默认 5s
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// IsEnabled 返回是否启用健康检查
+func (c HealthConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// ReadinessCheck 就绪检查函数，返回 error 表示未就绪
+type ReadinessCheck func(ctx context.Context) error
+
+// CheckResult 单项就绪检查结果
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessResult 就绪检查响应
+type ReadinessResult struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// readinessChecks 已注册的就绪检查
+type readinessChecks struct {
+	mu     sync.RWMutex
+	checks map[string]ReadinessCheck
+}
+
+// AddReadinessCheck 注册就绪检查，同名检查会被替换。
+//
+// 示例：
+//
+//	ginSvc.AddReadinessCheck("db", func(ctx context.Context) error {
+//		db, err := dbSvc.Default(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		sqlDB, err := db.DB()
+//		if err != nil {
+//			return err
+//		}
+//		return sqlDB.PingContext(ctx)
+//	})
+func (s *GinService) AddReadinessCheck(name string, check ReadinessCheck) {
+	s.readiness.mu.Lock()
+	defer s.readiness.mu.Unlock()
+	if s.readiness.checks == nil {
+		s.readiness.checks = make(map[string]ReadinessCheck)
+	}
+	s.readiness.checks[name] = check
+}
+
+// registerHealthRoutes 根据配置注册存活、就绪检查路由，只注册一次
+//
+// 业务已注册同路径的 GET 路由时跳过该路径，保留业务路由，避免 gin 因重复路由 panic。
+func (s *GinService) registerHealthRoutes() {
+	s.healthOnce.Do(func() {
+		cfg := s.config.Health
+		if !cfg.IsEnabled() {
+			return
+		}
+		livenessPath := cfg.LivenessPath
+		if livenessPath == "" {
+			livenessPath = defaultLivenessPath
+		}
+		readinessPath := cfg.ReadinessPath
+		if readinessPath == "" {
+			readinessPath = defaultReadinessPath
+		}
+
+		if !s.hasRoute(http.MethodGet, livenessPath) {
+			s.engine.GET(livenessPath, func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": HealthStatusOK})
+			})
+		}
+		if s.hasRoute(http.MethodGet, readinessPath) {
+			return
+		}
+		s.engine.GET(readinessPath, func(c *gin.Context) {
+			timeout := s.config.Health.Timeout
+			if timeout <= 0 {
+				timeout = defaultHealthTimeout
+			}
+			ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+			defer cancel()
+
+			result := s.checkReadiness(ctx)
+			code := http.StatusOK
+			if result.Status != HealthStatusOK {
+				code = http.StatusServiceUnavailable
+			}
+			c.JSON(code, result)
+		})
+	})
+}
+
+// hasRoute 判断 engine 是否已注册指定方法与路径的路由
+func (s *GinService) hasRoute(method, path string) bool {
+	for _, route := range s.engine.Routes() {
+		if route.Method == method && route.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReadiness 并发执行所有就绪检查并汇总结果
+func (s *GinService) checkReadiness(ctx context.Context) ReadinessResult {
+	s.readiness.mu.RLock()
+	checks := make(map[string]ReadinessCheck, len(s.readiness.checks))
+	for name, check := range s.readiness.checks {
+		checks[name] = check
+	}
+	s.readiness.mu.RUnlock()
+
+	result := ReadinessResult{
+		Status: HealthStatusOK,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check ReadinessCheck) {
+			defer wg.Done()
+			err := runReadinessCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Status = HealthStatusFail
+				result.Checks[name] = CheckResult{Status: HealthStatusFail, Error: err.Error()}
+				return
+			}
+			result.Checks[name] = CheckResult{Status: HealthStatusOK}
+		}(name, check)
+	}
+	wg.Wait()
+	return result
+}
+
+// runReadinessCheck 执行单项检查，检查未响应 ctx 时在超时后返回 ctx 错误，
+// 检查 panic 时视为失败，避免后台协程 panic 导致进程退出
+func runReadinessCheck(ctx context.Context, check ReadinessCheck) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
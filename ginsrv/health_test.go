@@ -0,0 +1,192 @@
+package ginsrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHealthService(t *testing.T, settings map[string]any) *GinService {
+	t.Helper()
+	service := New()
+	service.init()
+
+	v := viper.New()
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+	require.NoError(t, v.Unmarshal(service.config, withYamlTag))
+	service.registerHealthRoutes()
+	return service
+}
+
+func doHealthRequest(service *GinService, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	service.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestGinService_Healthz(t *testing.T) {
+	service := newHealthService(t, nil)
+	// 就绪检查失败不影响存活检查
+	service.AddReadinessCheck("db", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	w := doHealthRequest(service, "/healthz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestGinService_Readyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     map[string]ReadinessCheck
+		wantCode   int
+		wantResult ReadinessResult
+	}{
+		{
+			name:     "无检查项",
+			wantCode: http.StatusOK,
+			wantResult: ReadinessResult{
+				Status: HealthStatusOK,
+				Checks: map[string]CheckResult{},
+			},
+		},
+		{
+			name: "全部通过",
+			checks: map[string]ReadinessCheck{
+				"db":    func(ctx context.Context) error { return nil },
+				"redis": func(ctx context.Context) error { return nil },
+			},
+			wantCode: http.StatusOK,
+			wantResult: ReadinessResult{
+				Status: HealthStatusOK,
+				Checks: map[string]CheckResult{
+					"db":    {Status: HealthStatusOK},
+					"redis": {Status: HealthStatusOK},
+				},
+			},
+		},
+		{
+			name: "部分失败",
+			checks: map[string]ReadinessCheck{
+				"db":    func(ctx context.Context) error { return nil },
+				"redis": func(ctx context.Context) error { return errors.New("dial tcp: connection refused") },
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantResult: ReadinessResult{
+				Status: HealthStatusFail,
+				Checks: map[string]CheckResult{
+					"db":    {Status: HealthStatusOK},
+					"redis": {Status: HealthStatusFail, Error: "dial tcp: connection refused"},
+				},
+			},
+		},
+		{
+			name: "检查 panic",
+			checks: map[string]ReadinessCheck{
+				"db": func(ctx context.Context) error { panic("nil pool") },
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantResult: ReadinessResult{
+				Status: HealthStatusFail,
+				Checks: map[string]CheckResult{
+					"db": {Status: HealthStatusFail, Error: "panic: nil pool"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newHealthService(t, nil)
+			for name, check := range tt.checks {
+				service.AddReadinessCheck(name, check)
+			}
+
+			w := doHealthRequest(service, "/readyz")
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			var result ReadinessResult
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, tt.wantResult, result)
+		})
+	}
+}
+
+func TestGinService_Readyz_Timeout(t *testing.T) {
+	service := newHealthService(t, map[string]any{"health.timeout": "50ms"})
+	block := make(chan struct{})
+	defer close(block)
+	// 不响应 ctx 的检查也应在超时后返回
+	service.AddReadinessCheck("slow", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	start := time.Now()
+	w := doHealthRequest(service, "/readyz")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var result ReadinessResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, CheckResult{Status: HealthStatusFail, Error: context.DeadlineExceeded.Error()}, result.Checks["slow"])
+}
+
+func TestGinService_AddReadinessCheck_Replace(t *testing.T) {
+	service := newHealthService(t, nil)
+	service.AddReadinessCheck("db", func(ctx context.Context) error { return errors.New("down") })
+	service.AddReadinessCheck("db", func(ctx context.Context) error { return nil })
+
+	w := doHealthRequest(service, "/readyz")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGinService_HealthConfig(t *testing.T) {
+	t.Run("自定义路径", func(t *testing.T) {
+		service := newHealthService(t, map[string]any{
+			"health.liveness_path":  "/live",
+			"health.readiness_path": "/ready",
+		})
+		assert.Equal(t, http.StatusOK, doHealthRequest(service, "/live").Code)
+		assert.Equal(t, http.StatusOK, doHealthRequest(service, "/ready").Code)
+		assert.Equal(t, http.StatusNotFound, doHealthRequest(service, "/healthz").Code)
+	})
+
+	t.Run("禁用", func(t *testing.T) {
+		service := newHealthService(t, map[string]any{"health.enabled": false})
+		assert.Equal(t, http.StatusNotFound, doHealthRequest(service, "/healthz").Code)
+		assert.Equal(t, http.StatusNotFound, doHealthRequest(service, "/readyz").Code)
+	})
+
+	t.Run("路径已被业务注册", func(t *testing.T) {
+		service := New()
+		service.init()
+		service.Engine().GET("/healthz", func(c *gin.Context) {
+			c.String(http.StatusOK, "custom")
+		})
+
+		assert.NotPanics(t, service.registerHealthRoutes)
+		w := doHealthRequest(service, "/healthz")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "custom", w.Body.String())
+		// 未冲突的就绪检查路由仍正常注册
+		assert.Equal(t, http.StatusOK, doHealthRequest(service, "/readyz").Code)
+	})
+
+	t.Run("重复注册", func(t *testing.T) {
+		service := newHealthService(t, nil)
+		assert.NotPanics(t, service.registerHealthRoutes)
+	})
+}
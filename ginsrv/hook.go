@@ -0,0 +1,47 @@
+package ginsrv
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Hook 生命周期钩子，返回的错误只记录日志，不影响服务启动和关闭。
+type Hook func(ctx context.Context) error
+
+// OnStart 注册启动钩子，在 Run 中 HTTP/HTTPS 服务器开始监听后按注册顺序执行，
+// 可用于服务注册等需要端口已就绪的操作。
+func (s *GinService) OnStart(fn Hook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.onStart = append(s.onStart, fn)
+}
+
+// OnShutdown 注册关闭钩子，在 Close 中关闭服务器前按注册顺序执行，
+// ctx 受 shutdown_timeout 限制，可用于服务注销等操作。
+func (s *GinService) OnShutdown(fn Hook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.onShutdown = append(s.onShutdown, fn)
+}
+
+func (s *GinService) startHooks() []Hook {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	return append([]Hook(nil), s.onStart...)
+}
+
+func (s *GinService) shutdownHooks() []Hook {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	return append([]Hook(nil), s.onShutdown...)
+}
+
+// runHooks 按顺序执行钩子，错误记录日志后继续执行后续钩子
+func (s *GinService) runHooks(ctx context.Context, logger *zap.Logger, stage string, hooks []Hook) {
+	for i, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			logger.Error("gin "+stage+" hook failed", zap.Int("index", i), zap.Error(err))
+		}
+	}
+}
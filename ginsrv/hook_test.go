@@ -0,0 +1,112 @@
+package ginsrv
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGinService_Hooks 测试启动、关闭钩子按顺序执行
+func TestGinService_Hooks(t *testing.T) {
+	service := New(WithName("test-hooks"))
+	config := &Config{Mode: "test", Host: "localhost"}
+	config.Http.Enabled = true
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	record := func(name string, err error) Hook {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name)
+			return err
+		}
+	}
+	started := make(chan struct{})
+
+	service.OnStart(record("start-1", nil))
+	// 钩子返回错误时记录日志并继续执行后续钩子
+	service.OnStart(record("start-2", errors.New("register failed")))
+	service.OnStart(func(ctx context.Context) error {
+		// 启动钩子执行时服务器已开始监听
+		require.NotNil(t, service.httpServer)
+		close(started)
+		return nil
+	})
+	service.OnShutdown(record("shutdown-1", nil))
+	service.OnShutdown(func(ctx context.Context) error {
+		// 关闭钩子在服务器关闭前执行，ctx 带有超时
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return record("shutdown-2", nil)(ctx)
+	})
+
+	ctx := createTestContext(t, "test-hooks", config)
+	require.NoError(t, service.Boot(ctx))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- service.Run(runCtx)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("启动钩子未执行")
+	}
+	mu.Lock()
+	assert.Equal(t, []string{"start-1", "start-2"}, calls)
+	mu.Unlock()
+
+	require.NoError(t, service.Close(ctx))
+	mu.Lock()
+	assert.Equal(t, []string{"start-1", "start-2", "shutdown-1", "shutdown-2"}, calls)
+	mu.Unlock()
+
+	// 重复关闭（如信号处理与 drugo.Shutdown 同时触发）不会再次执行关闭钩子
+	require.NoError(t, service.Close(ctx))
+	mu.Lock()
+	assert.Equal(t, []string{"start-1", "start-2", "shutdown-1", "shutdown-2"}, calls)
+	mu.Unlock()
+
+	cancel()
+	select {
+	case err := <-errChan:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务未在预期时间内停止")
+	}
+}
+
+// TestGinService_Run_ListenError 测试端口被占用时 Run 直接返回错误且不执行启动钩子
+func TestGinService_Run_ListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	service := New(WithName("test-listen"))
+	config := &Config{Mode: "test", Host: "127.0.0.1"}
+	config.Http.Enabled = true
+	config.Http.Port = ln.Addr().(*net.TCPAddr).Port
+
+	ctx := createTestContext(t, "test-listen", config)
+	require.NoError(t, service.Boot(ctx))
+	service.OnStart(func(ctx context.Context) error {
+		t.Error("启动钩子不应执行")
+		return nil
+	})
+
+	err = service.Run(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "http listen")
+}
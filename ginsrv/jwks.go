@@ -0,0 +1,237 @@
+package ginsrv
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksMinRefresh 两次拉取 JWKS 的最小间隔：遇到未知 kid 或拉取失败后，间隔内不再重新拉取，
+// 避免伪造 kid 或 JWKS 服务故障时每个请求都发起拉取。
+const jwksMinRefresh = 10 * time.Second
+
+// jwks 从 JWKS 地址拉取并缓存公钥。
+//
+// 定期刷新失败时继续使用已缓存的公钥；拉取在锁外进行，并发请求共享同一次拉取。
+type jwks struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+	now     func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetched   time.Time // 最近一次成功拉取的时间
+	attempted time.Time // 最近一次拉取的时间，无论成败
+	err       error     // 最近一次拉取的错误
+	call      *jwksCall // 进行中的拉取
+}
+
+// jwksCall 一次进行中的拉取，done 关闭后 err 可读。
+type jwksCall struct {
+	done chan struct{}
+	err  error
+}
+
+func newJWKS(url string, refresh time.Duration) *jwks {
+	if refresh <= 0 {
+		refresh = time.Hour
+	}
+	return &jwks{
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		now:     time.Now,
+	}
+}
+
+// keyFunc 实现 jwt.Keyfunc，按 token 头中的 kid 返回公钥；JWKS 只有一个公钥时 kid 可省略。
+func (s *jwks) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	if s.shouldFetch(true) {
+		// 失败时保留原有公钥，下面的查找继续使用缓存
+		_ = s.update()
+	}
+	key, ok, err := s.lookup(kid)
+	if !ok && err == nil && s.shouldFetch(false) {
+		// 公钥可能已轮换，重新拉取
+		_ = s.update()
+		key, ok, err = s.lookup(kid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("jwks: key %q not found", kid)
+	}
+	return key, nil
+}
+
+// shouldFetch 判断是否需要拉取：距上次拉取不足 jwksMinRefresh 时不拉取；
+// expiredOnly 为 true 时仅在尚无公钥或缓存超过刷新间隔时拉取。
+func (s *jwks) shouldFetch(expiredOnly bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !s.attempted.IsZero() && now.Sub(s.attempted) < jwksMinRefresh {
+		return false
+	}
+	if !expiredOnly {
+		return true
+	}
+	return s.keys == nil || now.Sub(s.fetched) >= s.refresh
+}
+
+// lookup 查找公钥，尚未成功拉取过公钥时返回最近一次拉取的错误。
+func (s *jwks) lookup(kid string) (any, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keys == nil {
+		if s.err != nil {
+			return nil, false, s.err
+		}
+		return nil, false, errors.New("jwks: no keys available")
+	}
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true, nil
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok, nil
+}
+
+// update 拉取 JWKS 并更新缓存，失败时保留原有公钥；同一时间只有一个拉取，其余调用等待其结果。
+func (s *jwks) update() error {
+	s.mu.Lock()
+	if c := s.call; c != nil {
+		s.mu.Unlock()
+		<-c.done
+		return c.err
+	}
+	c := &jwksCall{done: make(chan struct{})}
+	s.call = c
+	s.mu.Unlock()
+
+	keys, err := s.fetch()
+
+	s.mu.Lock()
+	s.attempted = s.now()
+	if err == nil {
+		s.keys = keys
+		s.fetched = s.attempted
+	}
+	s.err = err
+	s.call = nil
+	s.mu.Unlock()
+
+	c.err = err
+	close(c.done)
+	return err
+}
+
+// fetch 拉取并解析 JWKS。
+func (s *jwks) fetch() (map[string]any, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: fetch: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: fetch: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwks: decode: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// 不支持的密钥类型直接忽略
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// jsonWebKey JWKS 中的单个公钥（RFC 7517）。
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey 将 JWK 转换为 *rsa.PublicKey 或 *ecdsa.PublicKey。
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwks: unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("jwks: unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("jwks: empty key parameter")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
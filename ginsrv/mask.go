@@ -0,0 +1,70 @@
+package ginsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// maskedValue 脱敏后的字段值
+const maskedValue = "***"
+
+// maskJSONBody 将 JSON body 中的敏感字段替换为 ***，返回新的 body，不修改原始数据。
+//
+// fields 为空或 body 不是 JSON 时原样返回；看起来是 JSON 但无法解析（如被截断）时返回 nil，
+// 避免敏感字段以原文写入日志。
+func maskJSONBody(body []byte, fields []string) []byte {
+	if len(fields) == 0 || len(body) == 0 {
+		return body
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil || dec.More() {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		set[strings.ToLower(f)] = struct{}{}
+	}
+	if !maskValue(data, set) {
+		return body
+	}
+
+	masked, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return masked
+}
+
+// maskValue 递归替换敏感字段，返回是否发生替换
+func maskValue(v any, fields map[string]struct{}) bool {
+	masked := false
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if _, ok := fields[strings.ToLower(k)]; ok {
+				val[k] = maskedValue
+				masked = true
+				continue
+			}
+			if maskValue(child, fields) {
+				masked = true
+			}
+		}
+	case []any:
+		for _, child := range val {
+			if maskValue(child, fields) {
+				masked = true
+			}
+		}
+	}
+	return masked
+}
@@ -0,0 +1,67 @@
+package ginsrv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskJSONBody(t *testing.T) {
+	fields := []string{"password", "Token"}
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+		want   string
+	}{
+		{
+			name:   "未配置脱敏字段",
+			body:   `{"password":"123456"}`,
+			fields: nil,
+			want:   `{"password":"123456"}`,
+		},
+		{
+			name:   "对象字段",
+			body:   `{"username":"admin","password":"123456","age":18}`,
+			fields: fields,
+			want:   `{"age":18,"password":"***","username":"admin"}`,
+		},
+		{
+			name:   "嵌套对象与数组，不区分大小写",
+			body:   `{"data":{"TOKEN":"abc","list":[{"password":"1"},{"name":"x"}]}}`,
+			fields: fields,
+			want:   `{"data":{"TOKEN":"***","list":[{"password":"***"},{"name":"x"}]}}`,
+		},
+		{
+			name:   "无敏感字段原样返回",
+			body:   `{"b":1, "a":2}`,
+			fields: fields,
+			want:   `{"b":1, "a":2}`,
+		},
+		{
+			name:   "大数字保持精度",
+			body:   `{"id":12345678901234567890,"password":"x"}`,
+			fields: fields,
+			want:   `{"id":12345678901234567890,"password":"***"}`,
+		},
+		{
+			name:   "非JSON原样返回",
+			body:   `password=123456`,
+			fields: fields,
+			want:   `password=123456`,
+		},
+		{
+			name:   "截断的JSON不记录",
+			body:   `{"username":"admin","password":"1234`,
+			fields: fields,
+			want:   ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(maskJSONBody([]byte(tt.body), tt.fields)))
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 )
 
 const (
-	maxBodySize          = 4 * 1024 // 最大记录4KB的body
+	maxBodySize          = 4 * 1024 // 默认最大记录4KB的body
 	defaultAccessLogName = "gin.access"
 )
 
@@ -19,17 +19,61 @@ const (
 type responseWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
+	// limit 最大捕获字节数，<= 0 时使用 maxBodySize
+	limit int
 }
 
 func (w *responseWriter) Write(b []byte) (int, error) {
-	if w.body.Len() < maxBodySize {
-		w.body.Write(b)
+	limit := w.limit
+	if limit <= 0 {
+		limit = maxBodySize
+	}
+	if remain := limit - w.body.Len(); remain > 0 {
+		if len(b) > remain {
+			w.body.Write(b[:remain])
+		} else {
+			w.body.Write(b)
+		}
 	}
 	return w.ResponseWriter.Write(b)
 }
 
+// AccessLogOption 访问日志中间件选项
+type AccessLogOption func(*accessLogOptions)
+
+type accessLogOptions struct {
+	maxBodySize func() int
+	traceHeader func() string
+	maskFields  func() []string
+}
+
+// WithMaxBodySize 设置记录请求、响应 body 的最大字节数，默认 4KB，0 表示不记录 body。
+func WithMaxBodySize(n int) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.maxBodySize = func() int { return n }
+	}
+}
+
+// WithTraceHeader 设置 trace id 请求头，未注册 TraceMiddleware 时从该请求头读取 trace id。
+func WithTraceHeader(header string) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.traceHeader = func() string { return header }
+	}
+}
+
+// WithMaskFields 设置需要脱敏的 JSON 字段（不区分大小写），日志中字段值替换为 ***。
+// 非 JSON body 原样记录，无法解析的 JSON（如被截断）不记录。
+func WithMaskFields(fields ...string) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.maskFields = func() []string { return fields }
+	}
+}
+
 // AccessLogger 是用于记录请求、响应日志的中间件
-func AccessLogger(lmg interface{ MustGet(string) *zap.Logger }, accessLogName string, errLogName string) gin.HandlerFunc {
+//
+// 请求、响应 body 最多记录 maxBodySize 字节（可通过 WithMaxBodySize 调整），
+// 超出部分在日志中截断，不影响处理器读取完整的请求 body。
+func AccessLogger(lmg interface{ MustGet(string) *zap.Logger }, accessLogName string, errLogName string, opts ...AccessLogOption) gin.HandlerFunc {
 	if accessLogName == "" {
 		accessLogName = defaultAccessLogName
 	}
@@ -37,6 +81,13 @@ func AccessLogger(lmg interface{ MustGet(string) *zap.Logger }, accessLogName st
 		errLogName = defaultErrorLogName
 	}
 
+	o := &accessLogOptions{
+		maxBodySize: func() int { return maxBodySize },
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	accessLogger := lmg.MustGet(accessLogName)
 	errorLogger := lmg.MustGet(errLogName)
 
@@ -45,21 +96,35 @@ func AccessLogger(lmg interface{ MustGet(string) *zap.Logger }, accessLogName st
 
 		// ⭐ 获取 trace_id
 		traceID := GetTraceID(c)
+		if traceID == "" && o.traceHeader != nil {
+			if header := o.traceHeader(); header != "" {
+				traceID = c.GetHeader(header)
+			}
+		}
+
+		bodyLimit := o.maxBodySize()
 
-		// 读取请求body
+		// 读取请求body（最多 bodyLimit 字节），未读取的部分保留给处理器
 		var requestBody []byte
-		if c.Request.Body != nil {
-			bodyBytes, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxBodySize))
+		if bodyLimit > 0 && c.Request.Body != nil {
+			bodyBytes, _ := io.ReadAll(io.LimitReader(c.Request.Body, int64(bodyLimit)))
 			requestBody = bodyBytes
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 重新设置请求body
+			c.Request.Body = readCloser{
+				Reader: io.MultiReader(bytes.NewReader(bodyBytes), c.Request.Body),
+				Closer: c.Request.Body,
+			}
 		}
 
 		// 替换响应Writer以捕获响应body
-		bw := &responseWriter{
-			ResponseWriter: c.Writer,
-			body:           bytes.NewBuffer(nil),
+		var bw *responseWriter
+		if bodyLimit > 0 {
+			bw = &responseWriter{
+				ResponseWriter: c.Writer,
+				body:           bytes.NewBuffer(nil),
+				limit:          bodyLimit,
+			}
+			c.Writer = bw
 		}
-		c.Writer = bw
 
 		// 处理请求
 		c.Next()
@@ -79,8 +144,16 @@ func AccessLogger(lmg interface{ MustGet(string) *zap.Logger }, accessLogName st
 			zap.String("user_agent", c.Request.UserAgent()),
 			zap.Duration("latency", latency),
 			zap.Int("size", c.Writer.Size()),
-			zap.ByteString("request", requestBody),
-			zap.ByteString("response", bw.body.Bytes()),
+		}
+		if bw != nil {
+			var maskFields []string
+			if o.maskFields != nil {
+				maskFields = o.maskFields()
+			}
+			fields = append(fields,
+				zap.ByteString("request", maskJSONBody(requestBody, maskFields)),
+				zap.ByteString("response", maskJSONBody(bw.body.Bytes(), maskFields)),
+			)
 		}
 
 		// 处理业务错误
@@ -103,6 +176,12 @@ func AccessLogger(lmg interface{ MustGet(string) *zap.Logger }, accessLogName st
 	}
 }
 
+// readCloser 组合 Reader 与原始 Body 的 Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
 // AccessLoggerWithoutBody 是用于记录访问日志但不记录请求和响应body的中间件
 func AccessLoggerWithoutBody(lmg interface{ MustGet(string) *zap.Logger }, accessLogName string, errLogName string) gin.HandlerFunc {
 	if accessLogName == "" {
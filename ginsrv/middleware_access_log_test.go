@@ -2,6 +2,7 @@ package ginsrv
 
 import (
 	"bytes"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/qq1060656096/drugo/log"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -197,6 +199,209 @@ func TestAccessLogger_BodySizeLimit(t *testing.T) {
 	assert.NotEmpty(t, traceID)
 }
 
+func TestAccessLogger_MaxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		opts         []AccessLogOption
+		body         string
+		wantRequest  string
+		wantResponse string
+		wantNoBody   bool
+	}{
+		{
+			name:         "小body完整记录",
+			opts:         []AccessLogOption{WithMaxBodySize(64)},
+			body:         "hello",
+			wantRequest:  "hello",
+			wantResponse: "hello",
+		},
+		{
+			name:         "大body截断",
+			opts:         []AccessLogOption{WithMaxBodySize(8)},
+			body:         strings.Repeat("abcd", 10),
+			wantRequest:  "abcdabcd",
+			wantResponse: "abcdabcd",
+		},
+		{
+			name:       "0不记录body",
+			opts:       []AccessLogOption{WithMaxBodySize(0)},
+			body:       "hello",
+			wantNoBody: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			lm := &mockLogManager{accessLogger: zap.New(core), errorLogger: zap.New(core)}
+
+			router := gin.New()
+			router.Use(AccessLogger(lm, "gin.access", "gin.error", tt.opts...))
+			router.POST("/echo", func(c *gin.Context) {
+				body, err := io.ReadAll(c.Request.Body)
+				require.NoError(t, err)
+				// 处理器应读到完整的请求 body
+				assert.Equal(t, tt.body, string(body))
+				c.String(http.StatusOK, string(body))
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body)))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+
+			entries := logs.FilterMessage("request success").All()
+			require.Len(t, entries, 1)
+			fields := entries[0].ContextMap()
+			if tt.wantNoBody {
+				assert.NotContains(t, fields, "request")
+				assert.NotContains(t, fields, "response")
+				return
+			}
+			assert.Equal(t, tt.wantRequest, fields["request"])
+			assert.Equal(t, tt.wantResponse, fields["response"])
+		})
+	}
+}
+
+func TestGinService_AccessLogger_Config(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	v := viper.New()
+	v.Set("access_log.max_body_size", 4)
+	service := New()
+	service.init()
+	require.NoError(t, v.Unmarshal(service.config, withYamlTag))
+	assert.Equal(t, 4, service.config.AccessLog.BodySize())
+
+	core, logs := observer.New(zap.InfoLevel)
+	router := gin.New()
+	logger := zap.New(core)
+	router.Use(service.AccessLogger(&mockLogManager{accessLogger: logger, errorLogger: logger}, "gin.access", "gin.error"))
+	router.POST("/echo", func(c *gin.Context) {
+		c.String(http.StatusOK, "response")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("request")))
+
+	entries := logs.FilterMessage("request success").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "requ", entries[0].ContextMap()["request"])
+	assert.Equal(t, "resp", entries[0].ContextMap()["response"])
+
+	// 未配置时使用默认值
+	assert.Equal(t, maxBodySize, AccessLogConfig{}.BodySize())
+}
+
+// TestGinService_AccessLogger_ConfigLoadedAfterRegister 测试先注册中间件、后加载配置（与 Run 的顺序一致）时配置仍生效
+func TestGinService_AccessLogger_ConfigLoadedAfterRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := New()
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	engine := service.Engine()
+	engine.Use(service.AccessLogger(&mockLogManager{accessLogger: logger, errorLogger: logger}, "gin.access", "gin.error"))
+	engine.POST("/echo", func(c *gin.Context) {
+		c.String(http.StatusOK, "response")
+	})
+
+	v := viper.New()
+	v.Set("access_log.max_body_size", 4)
+	require.NoError(t, v.Unmarshal(service.config, withYamlTag))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("request")))
+
+	entries := logs.FilterMessage("request success").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "requ", entries[0].ContextMap()["request"])
+	assert.Equal(t, "resp", entries[0].ContextMap()["response"])
+}
+
+func TestAccessLogger_WithTraceHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	lm := &mockLogManager{accessLogger: logger, errorLogger: logger}
+
+	// 未注册 TraceMiddleware 时从请求头读取 trace id
+	router := gin.New()
+	router.Use(AccessLogger(lm, "gin.access", "gin.error", WithTraceHeader("X-Trace-Id")))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Trace-Id", "header-trace-id")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "header-trace-id", entries[0].ContextMap()["trace_id"])
+}
+
+func TestAccessLogger_MaskFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	lm := &mockLogManager{accessLogger: logger, errorLogger: logger}
+
+	router := gin.New()
+	router.Use(AccessLogger(lm, "gin.access", "gin.error", WithMaskFields("password", "token")))
+	router.POST("/login", func(c *gin.Context) {
+		var req struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		require.NoError(t, c.ShouldBindJSON(&req))
+		// 处理器收到真实的值
+		assert.Equal(t, "123456", req.Password)
+		c.JSON(http.StatusOK, gin.H{"token": "secret-token"})
+	})
+
+	body := `{"username":"admin","password":"123456"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"token":"secret-token"}`, w.Body.String())
+
+	entries := logs.FilterMessage("request success").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.JSONEq(t, `{"username":"admin","password":"***"}`, fields["request"].(string))
+	assert.JSONEq(t, `{"token":"***"}`, fields["response"].(string))
+}
+
+func TestGinService_AccessLogger_MaskFieldsConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	v := viper.New()
+	v.Set("access_log.mask_fields", []string{"password"})
+	service := New()
+	service.init()
+	require.NoError(t, v.Unmarshal(service.config, withYamlTag))
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	router := gin.New()
+	router.Use(service.AccessLogger(&mockLogManager{accessLogger: logger, errorLogger: logger}, "gin.access", "gin.error"))
+	router.POST("/login", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"password":"123456"}`)))
+
+	entries := logs.FilterMessage("request success").All()
+	require.Len(t, entries, 1)
+	assert.JSONEq(t, `{"password":"***"}`, entries[0].ContextMap()["request"].(string))
+}
+
 func TestResponseWriter(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -215,8 +420,8 @@ func TestResponseWriter(t *testing.T) {
 		{
 			name:             "大响应体",
 			bodySize:         maxBodySize + 1000,
-			expectBufferSize: maxBodySize + 1000, // 实际行为：整个数据块都会被写入
-			description:      "由于实现逻辑，整个数据块都会被写入缓冲区",
+			expectBufferSize: maxBodySize,
+			description:      "超出部分应该被截断",
 		},
 	}
 
@@ -244,15 +449,8 @@ func TestResponseWriter(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.bodySize, n) // 实际写入的字节数应该是请求的字节数
 
-				// 验证缓冲区大小 - 根据实际实现调整期望值
-				if tt.bodySize <= maxBodySize {
-					// 小数据应该完全写入缓冲区
-					assert.Equal(t, tt.expectBufferSize, capturedWriter.body.Len(), tt.description)
-				} else {
-					// 大数据：由于实现逻辑，整个数据块都会被写入
-					// 这是因为检查是在写入前进行的
-					assert.Equal(t, tt.expectBufferSize, capturedWriter.body.Len(), tt.description)
-				}
+				// 验证缓冲区大小
+				assert.Equal(t, tt.expectBufferSize, capturedWriter.body.Len(), tt.description)
 
 				c.Status(http.StatusOK)
 			})
@@ -0,0 +1,76 @@
+package ginsrv
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
+)
+
+// ErrUnauthorized 认证失败时返回的错误（HTTP 401）。
+var ErrUnauthorized = errcode.New(1014010001, "unauthorized")
+
+// BasicAuthUserKey 认证成功后用户名在 gin.Context 中的键名，与 gin.BasicAuth 保持一致。
+const BasicAuthUserKey = gin.AuthUserKey
+
+// BasicAuth 创建 HTTP Basic 认证中间件，适用于内部管理接口。
+//
+// users 为用户名到密码的映射，realm 为空时使用 "Authorization Required"。
+// 认证失败时设置 WWW-Authenticate 响应头并通过 ginresp 返回 401；
+// 认证成功时将用户名写入 gin.Context，可通过 GetVar[string](c, BasicAuthUserKey) 获取。
+// 密码使用常量时间比较，用户名不存在时同样执行一次比较，避免通过响应时间探测用户名。
+//
+// 示例：
+//
+//	admin := service.Group("/admin", ginsrv.BasicAuth(map[string]string{"admin": "secret"}, ""))
+func BasicAuth(users map[string]string, realm string) gin.HandlerFunc {
+	if realm == "" {
+		realm = "Authorization Required"
+	}
+	challenge := "Basic realm=" + strconv.Quote(realm)
+
+	// 预先计算密码摘要，比较定长摘要使耗时与密码长度无关
+	digests := make(map[string][sha256.Size]byte, len(users))
+	for user, password := range users {
+		digests[user] = sha256.Sum256([]byte(password))
+	}
+
+	return func(c *gin.Context) {
+		user, ok := checkBasicAuth(c.GetHeader("Authorization"), digests)
+		if !ok {
+			c.Header("WWW-Authenticate", challenge)
+			ginresp.AbortErr(c, ErrUnauthorized, nil)
+			return
+		}
+		c.Set(BasicAuthUserKey, user)
+		c.Next()
+	}
+}
+
+// checkBasicAuth 解析 Authorization 请求头并校验用户名密码。
+func checkBasicAuth(header string, digests map[string][sha256.Size]byte) (string, bool) {
+	const prefix = "Basic "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(header[len(prefix):]))
+	if err != nil {
+		return "", false
+	}
+	user, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", false
+	}
+
+	expected, exists := digests[user]
+	actual := sha256.Sum256([]byte(password))
+	if subtle.ConstantTimeCompare(actual[:], expected[:]) != 1 || !exists {
+		return "", false
+	}
+	return user, true
+}
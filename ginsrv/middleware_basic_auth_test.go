@@ -0,0 +1,75 @@
+package ginsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(BasicAuth(map[string]string{"admin": "secret", "ops": ""}, "admin area"))
+	r.GET("/admin", func(c *gin.Context) {
+		user, _ := GetVar[string](c, BasicAuthUserKey)
+		c.String(http.StatusOK, "hello "+user)
+	})
+
+	do := func(setup func(req *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if setup != nil {
+			setup(req)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("认证成功", func(t *testing.T) {
+		w := do(func(req *http.Request) { req.SetBasicAuth("admin", "secret") })
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "hello admin", w.Body.String())
+
+		// 空密码用户
+		w = do(func(req *http.Request) { req.SetBasicAuth("ops", "") })
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "hello ops", w.Body.String())
+	})
+
+	t.Run("认证失败", func(t *testing.T) {
+		for name, setup := range map[string]func(req *http.Request){
+			"密码错误":     func(req *http.Request) { req.SetBasicAuth("admin", "wrong") },
+			"用户不存在":    func(req *http.Request) { req.SetBasicAuth("guest", "secret") },
+			"不存在用户空密码": func(req *http.Request) { req.SetBasicAuth("guest", "") },
+			"非 Basic":  func(req *http.Request) { req.Header.Set("Authorization", "Bearer token") },
+			"编码错误":     func(req *http.Request) { req.Header.Set("Authorization", "Basic !!!") },
+		} {
+			w := do(setup)
+			assert.Equal(t, http.StatusUnauthorized, w.Code, name)
+			assert.Equal(t, `Basic realm="admin area"`, w.Header().Get("WWW-Authenticate"), name)
+			assert.Contains(t, w.Body.String(), "unauthorized", name)
+		}
+	})
+
+	t.Run("缺少请求头", func(t *testing.T) {
+		w := do(nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Basic realm="admin area"`, w.Header().Get("WWW-Authenticate"))
+	})
+}
+
+func TestBasicAuth_DefaultRealm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/", BasicAuth(nil, ""), func(c *gin.Context) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="Authorization Required"`, w.Header().Get("WWW-Authenticate"))
+}
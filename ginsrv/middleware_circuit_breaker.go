@@ -0,0 +1,241 @@
+package ginsrv
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
+)
+
+// CircuitState 熔断器状态。
+type CircuitState int
+
+const (
+	// CircuitClosed 关闭状态：请求正常通过，统计失败率。
+	CircuitClosed CircuitState = iota
+	// CircuitOpen 打开状态：请求直接返回 503。
+	CircuitOpen
+	// CircuitHalfOpen 半开状态：放行少量探测请求。
+	CircuitHalfOpen
+)
+
+// String 返回状态名称。
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrCircuitOpen 熔断器打开时返回的错误（HTTP 503）。
+var ErrCircuitOpen = errcode.New(1015030001, "service unavailable: circuit breaker is open")
+
+// CircuitBreakerOptions 熔断器配置。
+type CircuitBreakerOptions struct {
+	// MinRequests 统计窗口内达到该请求数后才计算失败率，默认 10
+	MinRequests int
+	// FailureRatio 失败率阈值，达到后熔断，默认 0.5
+	FailureRatio float64
+	// Interval 关闭状态下统计窗口长度，到期清零计数，0 表示不清零
+	Interval time.Duration
+	// OpenTimeout 打开状态持续时长，到期进入半开状态，默认 30s
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests 半开状态允许的探测请求数，全部成功后关闭熔断器，默认 1
+	HalfOpenMaxRequests int
+	// IsFailure 判断请求是否失败，默认 c.Errors 非空或响应状态码 >= 500
+	IsFailure func(c *gin.Context) bool
+	// OnStateChange 状态变化回调（可选），在熔断器锁内调用，不应阻塞
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker 创建熔断中间件。
+//
+// 处理器通过 c.Error(err) 标记请求失败；失败率达到阈值后熔断器打开，
+// 期间请求直接通过 ginresp 返回 503，超时后进入半开状态放行探测请求。
+//
+// 示例：
+//
+//	bi := engine.Group("/api/bi", ginsrv.CircuitBreaker(ginsrv.CircuitBreakerOptions{
+//		FailureRatio: 0.6,
+//		OpenTimeout:  10 * time.Second,
+//	}))
+func CircuitBreaker(opts CircuitBreakerOptions) gin.HandlerFunc {
+	return newCircuitBreaker(opts, time.Now).middleware()
+}
+
+// circuitBreaker 熔断器内部实现。
+type circuitBreaker struct {
+	opts CircuitBreakerOptions
+	now  func() time.Time
+
+	mu         sync.Mutex
+	state      CircuitState
+	generation uint64 // 每次状态切换或窗口重置时递增，用于忽略过期的请求结果
+	expiry     time.Time
+	requests   int
+	failures   int
+	successes  int
+}
+
+func newCircuitBreaker(opts CircuitBreakerOptions, now func() time.Time) *circuitBreaker {
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = 10
+	}
+	if opts.FailureRatio <= 0 {
+		opts.FailureRatio = 0.5
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = 30 * time.Second
+	}
+	if opts.HalfOpenMaxRequests <= 0 {
+		opts.HalfOpenMaxRequests = 1
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = func(c *gin.Context) bool {
+			return len(c.Errors) > 0 || c.Writer.Status() >= http.StatusInternalServerError
+		}
+	}
+	cb := &circuitBreaker{
+		opts: opts,
+		now:  now,
+	}
+	cb.toNewGeneration(cb.now())
+	return cb
+}
+
+func (cb *circuitBreaker) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		generation, ok := cb.allow()
+		if !ok {
+			ginresp.AbortErr(c, ErrCircuitOpen, nil)
+			return
+		}
+
+		// 处理器 panic 时记为失败后继续抛出，避免半开状态的探测名额无法释放
+		defer func() {
+			if r := recover(); r != nil {
+				cb.done(generation, false)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		cb.done(generation, !cb.opts.IsFailure(c))
+	}
+}
+
+// State 返回当前状态。
+func (cb *circuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	state, _ := cb.currentState(cb.now())
+	return state
+}
+
+// allow 判断请求是否放行，返回请求所属的 generation。
+func (cb *circuitBreaker) allow() (uint64, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	state, generation := cb.currentState(cb.now())
+	switch state {
+	case CircuitOpen:
+		return generation, false
+	case CircuitHalfOpen:
+		if cb.requests >= cb.opts.HalfOpenMaxRequests {
+			return generation, false
+		}
+	}
+	cb.requests++
+	return generation, true
+}
+
+// done 记录请求结果。
+func (cb *circuitBreaker) done(generation uint64, success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	state, current := cb.currentState(now)
+	if generation != current {
+		return
+	}
+
+	switch state {
+	case CircuitClosed:
+		if !success {
+			cb.failures++
+		}
+		if cb.requests >= cb.opts.MinRequests &&
+			float64(cb.failures)/float64(cb.requests) >= cb.opts.FailureRatio {
+			cb.setState(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		if !success {
+			cb.setState(CircuitOpen, now)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.opts.HalfOpenMaxRequests {
+			cb.setState(CircuitClosed, now)
+		}
+	}
+}
+
+// currentState 返回当前状态，并处理窗口到期、打开超时等时间驱动的状态切换。
+func (cb *circuitBreaker) currentState(now time.Time) (CircuitState, uint64) {
+	switch cb.state {
+	case CircuitClosed:
+		if !cb.expiry.IsZero() && !now.Before(cb.expiry) {
+			cb.toNewGeneration(now)
+		}
+	case CircuitOpen:
+		if !now.Before(cb.expiry) {
+			cb.setState(CircuitHalfOpen, now)
+		}
+	}
+	return cb.state, cb.generation
+}
+
+func (cb *circuitBreaker) setState(state CircuitState, now time.Time) {
+	if cb.state == state {
+		return
+	}
+	prev := cb.state
+	cb.state = state
+	cb.toNewGeneration(now)
+	if cb.opts.OnStateChange != nil {
+		cb.opts.OnStateChange(prev, state)
+	}
+}
+
+func (cb *circuitBreaker) toNewGeneration(now time.Time) {
+	cb.generation++
+	cb.requests = 0
+	cb.failures = 0
+	cb.successes = 0
+
+	var zero time.Time
+	switch cb.state {
+	case CircuitClosed:
+		if cb.opts.Interval > 0 {
+			cb.expiry = now.Add(cb.opts.Interval)
+		} else {
+			cb.expiry = zero
+		}
+	case CircuitOpen:
+		cb.expiry = now.Add(cb.opts.OpenTimeout)
+	default:
+		cb.expiry = zero
+	}
+}
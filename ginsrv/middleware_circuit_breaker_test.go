@@ -0,0 +1,200 @@
+package ginsrv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_StateTransitions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	var transitions []string
+	cb := newCircuitBreaker(CircuitBreakerOptions{
+		MinRequests:         4,
+		FailureRatio:        0.5,
+		OpenTimeout:         10 * time.Second,
+		HalfOpenMaxRequests: 2,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	}, func() time.Time { return now })
+
+	fail := true
+	r := gin.New()
+	r.Use(cb.middleware())
+	r.GET("/bi", func(c *gin.Context) {
+		if fail {
+			_ = c.Error(errors.New("bi db timeout"))
+			c.String(http.StatusOK, "degraded")
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/bi", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// closed：失败率未达到最少请求数前不熔断
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, do().Code)
+		assert.Equal(t, CircuitClosed, cb.State())
+	}
+
+	// 第 4 个请求失败，失败率 100% -> open
+	assert.Equal(t, http.StatusOK, do().Code)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// open：短路返回 503
+	w := do()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "circuit breaker is open")
+
+	// 超时后进入 half-open
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+
+	// half-open 探测失败 -> 重新 open
+	assert.Equal(t, http.StatusOK, do().Code)
+	assert.Equal(t, CircuitOpen, cb.State())
+	assert.Equal(t, http.StatusServiceUnavailable, do().Code)
+
+	// 再次超时，探测全部成功 -> closed
+	now = now.Add(10 * time.Second)
+	fail = false
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	assert.Equal(t, http.StatusOK, do().Code)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	assert.Equal(t, http.StatusOK, do().Code)
+	assert.Equal(t, CircuitClosed, cb.State())
+	assert.Equal(t, "ok", do().Body.String())
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreaker_HalfOpenLimitsProbes(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newCircuitBreaker(CircuitBreakerOptions{
+		MinRequests:         1,
+		OpenTimeout:         time.Second,
+		HalfOpenMaxRequests: 1,
+	}, func() time.Time { return now })
+
+	gen, ok := cb.allow()
+	assert.True(t, ok)
+	cb.done(gen, false)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	now = now.Add(time.Second)
+	gen, ok = cb.allow()
+	assert.True(t, ok)
+	// 探测请求未完成时，其他请求被拒绝
+	_, ok = cb.allow()
+	assert.False(t, ok)
+
+	cb.done(gen, true)
+	assert.Equal(t, CircuitClosed, cb.State())
+}
+
+func TestCircuitBreaker_IntervalResetsCounts(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cb := newCircuitBreaker(CircuitBreakerOptions{
+		MinRequests:  2,
+		FailureRatio: 1,
+		Interval:     time.Minute,
+	}, func() time.Time { return now })
+
+	gen, _ := cb.allow()
+	cb.done(gen, false)
+
+	// 窗口到期后计数清零，旧窗口的失败不再计入
+	now = now.Add(time.Minute)
+	gen, _ = cb.allow()
+	cb.done(gen, false)
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	gen, _ = cb.allow()
+	cb.done(gen, false)
+	assert.Equal(t, CircuitOpen, cb.State())
+}
+
+func TestCircuitBreaker_DefaultFailureOnServerError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(CircuitBreaker(CircuitBreakerOptions{MinRequests: 1}))
+	r.GET("/bi", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/bi", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestCircuitBreaker_HalfOpenProbePanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	cb := newCircuitBreaker(CircuitBreakerOptions{
+		MinRequests: 1,
+		OpenTimeout: 10 * time.Second,
+	}, func() time.Time { return now })
+
+	panicking := true
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(cb.middleware())
+	r.GET("/bi", func(c *gin.Context) {
+		if panicking {
+			panic("bi handler crashed")
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	do := func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/bi", nil)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// closed 状态下 panic 记为失败 -> open
+	assert.Equal(t, http.StatusInternalServerError, do())
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// half-open 探测 panic：panic 继续抛出，熔断器重新 open 而不是一直占用探测名额
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	assert.Equal(t, http.StatusInternalServerError, do())
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// 下一次超时后探测成功即可恢复
+	now = now.Add(10 * time.Second)
+	panicking = false
+	assert.Equal(t, http.StatusOK, do())
+	assert.Equal(t, CircuitClosed, cb.State())
+}
@@ -7,16 +7,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/qq1060656096/bizutil/errcode"
 	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
-	"github.com/qq1060656096/drugo/log"
 	"go.uber.org/zap"
 )
 
 const (
 	defaultErrorLogName = "gin.error"
+	// maxStackSize 日志中记录的最大堆栈字节数，超出部分截断
+	maxStackSize = 16 * 1024
 )
 
-// RecoveryLogger 捕获panic并记录错误日志
-func RecoveryLogger(lmg *log.Manager, logName string) gin.HandlerFunc {
+// RecoveryLogger 捕获panic并记录错误日志，日志包含 error、stack、trace_id、path、method、ip 字段
+//
+// lmg 通常为 *log.Manager
+func RecoveryLogger(lmg interface{ MustGet(string) *zap.Logger }, logName string) gin.HandlerFunc {
 	if logName == "" {
 		logName = defaultErrorLogName
 	}
@@ -36,8 +39,9 @@ func RecoveryLogger(lmg *log.Manager, logName string) gin.HandlerFunc {
 				traceID := GetTraceID(c)
 
 				errorLogger.Error("panic recovered",
+					zap.Error(err),
 					zap.Any("recoverData", r),
-					zap.ByteString("stack", debug.Stack()),
+					zap.ByteString("stack", truncateStack(debug.Stack(), maxStackSize)),
 					zap.String("trace_id", traceID), // ⭐ 新增
 					zap.String("path", c.Request.URL.Path),
 					zap.String("method", c.Request.Method),
@@ -53,3 +57,14 @@ func RecoveryLogger(lmg *log.Manager, logName string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// truncateStack 截断超过 limit 字节的堆栈
+func truncateStack(stack []byte, limit int) []byte {
+	if len(stack) <= limit {
+		return stack
+	}
+	const suffix = "\n...(truncated)"
+	truncated := make([]byte, 0, limit+len(suffix))
+	truncated = append(truncated, stack[:limit]...)
+	return append(truncated, suffix...)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -460,3 +461,50 @@ func BenchmarkRecoveryLogger(b *testing.B) {
 		engine.ServeHTTP(w, req)
 	}
 }
+
+// TestRecoveryLogger_Stack 测试 panic 日志包含堆栈
+func TestRecoveryLogger_Stack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, observedLogs := createTestLogger(t)
+
+	engine := gin.New()
+	engine.Use(TraceMiddleware("X-Request-ID"))
+	engine.Use(RecoveryLogger(&mockLogManager{accessLogger: logger, errorLogger: logger}, ""))
+	engine.GET("/panic", func(c *gin.Context) {
+		panic(errors.New("boom"))
+	})
+
+	req := httptest.NewRequest("GET", "/panic", nil)
+	req.Header.Set("X-Request-ID", "stack-trace-id")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	logs := observedLogs.All()
+	if assert.Len(t, logs, 1) {
+		fields := logs[0].ContextMap()
+		assert.Equal(t, "panic recovered", logs[0].Message)
+		assert.Equal(t, "boom", fields["error"])
+		assert.Equal(t, "stack-trace-id", fields["trace_id"])
+		assert.Equal(t, "/panic", fields["path"])
+		assert.Equal(t, "GET", fields["method"])
+		assert.Contains(t, fields, "ip")
+
+		stack, _ := fields["stack"].(string)
+		assert.NotEmpty(t, stack)
+		assert.Contains(t, stack, "TestRecoveryLogger_Stack")
+	}
+}
+
+// TestTruncateStack 测试堆栈截断
+func TestTruncateStack(t *testing.T) {
+	small := []byte("goroutine 1 [running]:")
+	assert.Equal(t, small, truncateStack(small, 100))
+
+	large := []byte(strings.Repeat("x", 200))
+	truncated := truncateStack(large, 100)
+	assert.Equal(t, strings.Repeat("x", 100)+"\n...(truncated)", string(truncated))
+	// 不修改原始数据
+	assert.Len(t, large, 200)
+}
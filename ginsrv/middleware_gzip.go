@@ -0,0 +1,245 @@
+package ginsrv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultGzipMinSize 默认最小压缩大小，小于该大小的响应不压缩
+const defaultGzipMinSize = 1024
+
+// GzipOption Gzip 中间件选项
+type GzipOption func(*gzipOptions)
+
+type gzipOptions struct {
+	minSize int
+}
+
+// WithGzipMinSize 设置最小压缩大小，响应 body 达到该字节数才压缩，默认 1KB，<= 0 表示总是压缩。
+func WithGzipMinSize(n int) GzipOption {
+	return func(o *gzipOptions) {
+		o.minSize = n
+	}
+}
+
+// Gzip 创建响应压缩中间件，level 取值同 compress/gzip（如 gzip.DefaultCompression），非法时 panic。
+//
+// 仅在客户端声明 Accept-Encoding: gzip 且响应 body 达到最小压缩大小时压缩，
+// 压缩时设置 Content-Encoding 与 Vary 响应头并移除 Content-Length。
+//
+// 与 AccessLogger 同时使用时应先注册 Gzip，访问日志记录的是压缩前的 body：
+//
+//	engine.Use(ginsrv.Gzip(gzip.DefaultCompression), ginsrv.AccessLogger(lmg, "", ""))
+func Gzip(level int, opts ...GzipOption) gin.HandlerFunc {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		panic(fmt.Sprintf("ginsrv: invalid gzip level %d", level))
+	}
+
+	o := &gzipOptions{minSize: defaultGzipMinSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	pool := &sync.Pool{
+		New: func() any {
+			gz, _ := gzip.NewWriterLevel(io.Discard, level)
+			return gz
+		},
+	}
+
+	return func(c *gin.Context) {
+		if !shouldGzip(c.Request) {
+			c.Next()
+			return
+		}
+
+		gw := &gzipWriter{
+			ResponseWriter: c.Writer,
+			pool:           pool,
+			minSize:        o.minSize,
+		}
+		c.Writer = gw
+		defer gw.finish()
+
+		c.Next()
+	}
+}
+
+// shouldGzip 判断请求是否可以压缩响应
+func shouldGzip(req *http.Request) bool {
+	if req.Method == http.MethodHead {
+		return false
+	}
+	// WebSocket 等协议升级请求不压缩
+	if strings.Contains(strings.ToLower(req.Header.Get("Connection")), "upgrade") {
+		return false
+	}
+	return acceptsGzip(req.Header.Get("Accept-Encoding"))
+}
+
+// acceptsGzip 解析 Accept-Encoding，gzip 或 * 且 q 不为 0 时返回 true
+func acceptsGzip(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriterState 压缩写入器状态
+type gzipWriterState int
+
+const (
+	// gzipBuffering 缓冲中，尚未决定是否压缩
+	gzipBuffering gzipWriterState = iota
+	// gzipCompressing 已开始压缩输出
+	gzipCompressing
+	// gzipPassthrough 不压缩，直接输出
+	gzipPassthrough
+)
+
+// gzipWriter 缓冲响应 body，达到最小压缩大小后切换为 gzip 输出
+type gzipWriter struct {
+	gin.ResponseWriter
+	pool    *sync.Pool
+	minSize int
+
+	state gzipWriterState
+	buf   bytes.Buffer
+	gz    *gzip.Writer
+	// size 处理器写入的 body 字节数（压缩前）
+	size int
+}
+
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	switch w.state {
+	case gzipCompressing:
+		n, err := w.gz.Write(b)
+		w.size += n
+		return n, err
+	case gzipPassthrough:
+		n, err := w.ResponseWriter.Write(b)
+		w.size += n
+		return n, err
+	}
+
+	// 已设置编码或状态码不允许 body 时直接输出
+	if w.Header().Get("Content-Encoding") != "" || !bodyAllowedForStatus(w.Status()) {
+		w.passthrough()
+		return w.Write(b)
+	}
+
+	w.buf.Write(b)
+	w.size += len(b)
+	if w.buf.Len() > 0 && w.buf.Len() >= w.minSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow 立即发送响应头，缓冲的 body 不再压缩
+func (w *gzipWriter) WriteHeaderNow() {
+	if w.state == gzipBuffering {
+		w.passthrough()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush 根据已缓冲的数据决定是否压缩，并刷新到客户端
+func (w *gzipWriter) Flush() {
+	if w.state == gzipBuffering {
+		if w.buf.Len() > 0 && w.buf.Len() >= w.minSize {
+			_ = w.startGzip()
+		} else {
+			w.passthrough()
+		}
+	}
+	if w.state == gzipCompressing {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Size 返回处理器写入的 body 字节数（压缩前），未写入时与原始 Writer 保持一致
+func (w *gzipWriter) Size() int {
+	if w.size == 0 {
+		return w.ResponseWriter.Size()
+	}
+	return w.size
+}
+
+// startGzip 设置压缩响应头，并将缓冲数据写入 gzip
+func (w *gzipWriter) startGzip() error {
+	h := w.Header()
+	if h.Get("Content-Type") == "" {
+		// 避免 net/http 对压缩后的数据做类型探测
+		h.Set("Content-Type", http.DetectContentType(w.buf.Bytes()))
+	}
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", "gzip")
+	h.Add("Vary", "Accept-Encoding")
+
+	w.gz = w.pool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+	w.state = gzipCompressing
+
+	_, err := w.gz.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// passthrough 放弃压缩，原样输出缓冲数据
+func (w *gzipWriter) passthrough() {
+	w.state = gzipPassthrough
+	if w.buf.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// finish 请求结束时输出未达到压缩大小的数据或结束压缩流
+func (w *gzipWriter) finish() {
+	switch w.state {
+	case gzipBuffering:
+		w.passthrough()
+	case gzipCompressing:
+		_ = w.gz.Close()
+		w.gz.Reset(io.Discard)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// bodyAllowedForStatus 与 net/http 保持一致：1xx、204、304 不允许 body
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
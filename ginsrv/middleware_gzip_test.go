@@ -0,0 +1,180 @@
+package ginsrv
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newGzipRouter(body string, opts ...GzipOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Gzip(gzip.DefaultCompression, opts...))
+	router.GET("/data", func(c *gin.Context) {
+		c.String(http.StatusOK, body)
+	})
+	return router
+}
+
+func gunzip(t *testing.T, r io.Reader) string {
+	t.Helper()
+	zr, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGzip(t *testing.T) {
+	largeBody := strings.Repeat(`{"name":"drugo","value":123}`, 100)
+
+	tests := []struct {
+		name           string
+		body           string
+		acceptEncoding string
+		opts           []GzipOption
+		wantGzip       bool
+	}{
+		{
+			name:           "可压缩的响应",
+			body:           largeBody,
+			acceptEncoding: "gzip, deflate, br",
+			wantGzip:       true,
+		},
+		{
+			name:           "客户端不支持gzip",
+			body:           largeBody,
+			acceptEncoding: "",
+			wantGzip:       false,
+		},
+		{
+			name:           "客户端禁用gzip",
+			body:           largeBody,
+			acceptEncoding: "gzip;q=0, deflate",
+			wantGzip:       false,
+		},
+		{
+			name:           "小于最小压缩大小",
+			body:           "small",
+			acceptEncoding: "gzip",
+			wantGzip:       false,
+		},
+		{
+			name:           "自定义最小压缩大小",
+			body:           "small body",
+			acceptEncoding: "gzip",
+			opts:           []GzipOption{WithGzipMinSize(5)},
+			wantGzip:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newGzipRouter(tt.body, tt.opts...)
+
+			req := httptest.NewRequest(http.MethodGet, "/data", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if !tt.wantGzip {
+				assert.Empty(t, w.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.body, w.Body.String())
+				return
+			}
+
+			assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Empty(t, w.Header().Get("Content-Length"))
+			assert.Less(t, w.Body.Len(), len(tt.body)+100)
+			assert.Equal(t, tt.body, gunzip(t, w.Body))
+		})
+	}
+}
+
+func TestGzip_InvalidLevel(t *testing.T) {
+	assert.Panics(t, func() { Gzip(10) })
+	assert.Panics(t, func() { Gzip(-3) })
+	assert.NotPanics(t, func() { Gzip(gzip.BestSpeed) })
+}
+
+func TestGzip_NoContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Gzip(gzip.DefaultCompression, WithGzipMinSize(0)))
+	router.DELETE("/data", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/data", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Zero(t, w.Body.Len())
+}
+
+func TestGzip_WithAccessLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := strings.Repeat("hello drugo ", 200)
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	lm := &mockLogManager{accessLogger: logger, errorLogger: logger}
+
+	router := gin.New()
+	router.Use(Gzip(gzip.DefaultCompression), AccessLogger(lm, "gin.access", "gin.error", WithMaxBodySize(len(body))))
+	router.GET("/data", func(c *gin.Context) {
+		c.String(http.StatusOK, body)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/data", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, gunzip(t, w.Body))
+
+	// 访问日志记录压缩前的响应
+	entries := logs.FilterMessage("request success").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, body, fields["response"])
+	assert.Equal(t, int64(len(body)), fields["size"])
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"gzip", true},
+		{"deflate, gzip;q=0.8", true},
+		{"GZIP", true},
+		{"*", true},
+		{"", false},
+		{"deflate, br", false},
+		{"gzip;q=0", false},
+		{"gzip; q=0.000", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, acceptsGzip(tt.accept), tt.accept)
+	}
+}
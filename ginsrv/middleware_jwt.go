@@ -0,0 +1,116 @@
+package ginsrv
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
+)
+
+// JWTClaimsKey 认证成功后 claims（jwt.MapClaims）在 gin.Context 中的默认键名。
+const JWTClaimsKey = "jwt_claims"
+
+// JWTOptions JWT 认证配置，Secret、JWKSURL、KeyFunc 至少设置一个。
+type JWTOptions struct {
+	// Secret HMAC 签名密钥，默认允许 HS256/HS384/HS512
+	Secret []byte
+	// JWKSURL JWKS 地址，用于校验 RSA/ECDSA 签名，按 token 头中的 kid 选择公钥
+	JWKSURL string
+	// JWKSRefresh JWKS 缓存刷新间隔，默认 1 小时；遇到未知 kid 时会提前刷新
+	JWKSRefresh time.Duration
+	// KeyFunc 自定义验签密钥获取函数，设置后忽略 Secret 与 JWKSURL
+	KeyFunc jwt.Keyfunc
+	// Methods 允许的签名算法，默认根据密钥来源确定
+	Methods []string
+	// Header 读取 token 的请求头，默认 Authorization
+	Header string
+	// Prefix token 前缀（不区分大小写），默认 "Bearer "，设置为 "-" 表示没有前缀
+	Prefix string
+	// ContextKey claims 在 gin.Context 中的键名，默认 JWTClaimsKey
+	ContextKey string
+	// Leeway 校验 exp/nbf/iat 时允许的时钟偏差
+	Leeway time.Duration
+	// Issuer、Audience 非空时校验 iss、aud
+	Issuer   string
+	Audience string
+}
+
+// JWT 创建 JWT 认证中间件。
+//
+// 从请求头中提取 token，校验签名、过期时间等，成功后将 claims（jwt.MapClaims）写入 gin.Context，
+// 可通过 GetVar[jwt.MapClaims](c, JWTClaimsKey) 获取；失败时通过 ginresp 返回 401。
+// 配置无效（未设置任何验签密钥）时 panic。
+//
+// 示例：
+//
+//	api := service.Group("/api", ginsrv.JWT(ginsrv.JWTOptions{Secret: []byte(secret)}))
+func JWT(opts JWTOptions) gin.HandlerFunc {
+	keyFunc, methods := opts.KeyFunc, opts.Methods
+	switch {
+	case keyFunc != nil:
+	case opts.JWKSURL != "":
+		keyFunc = newJWKS(opts.JWKSURL, opts.JWKSRefresh).keyFunc
+		if len(methods) == 0 {
+			methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+		}
+	case len(opts.Secret) > 0:
+		secret := opts.Secret
+		keyFunc = func(*jwt.Token) (any, error) { return secret, nil }
+		if len(methods) == 0 {
+			methods = []string{"HS256", "HS384", "HS512"}
+		}
+	default:
+		panic("ginsrv: JWT requires Secret, JWKSURL or KeyFunc")
+	}
+
+	if opts.Header == "" {
+		opts.Header = "Authorization"
+	}
+	switch opts.Prefix {
+	case "":
+		opts.Prefix = "Bearer "
+	case "-":
+		opts.Prefix = ""
+	}
+	if opts.ContextKey == "" {
+		opts.ContextKey = JWTClaimsKey
+	}
+
+	parserOpts := []jwt.ParserOption{jwt.WithExpirationRequired(), jwt.WithLeeway(opts.Leeway)}
+	if len(methods) > 0 {
+		parserOpts = append(parserOpts, jwt.WithValidMethods(methods))
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+	parser := jwt.NewParser(parserOpts...)
+
+	return func(c *gin.Context) {
+		tokenString, ok := extractToken(c.GetHeader(opts.Header), opts.Prefix)
+		if !ok {
+			ginresp.AbortErr(c, ErrUnauthorized, nil)
+			return
+		}
+		claims := jwt.MapClaims{}
+		if _, err := parser.ParseWithClaims(tokenString, claims, keyFunc); err != nil {
+			ginresp.AbortErr(c, ErrUnauthorized, nil)
+			return
+		}
+		c.Set(opts.ContextKey, claims)
+		c.Next()
+	}
+}
+
+// extractToken 去掉前缀后返回 token，请求头为空或前缀不匹配时返回 false。
+func extractToken(header, prefix string) (string, bool) {
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(header[len(prefix):])
+	return token, token != ""
+}
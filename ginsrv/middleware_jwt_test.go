@@ -0,0 +1,302 @@
+package ginsrv
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testJWTSecret = []byte("test-secret")
+
+func signHS256(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testJWTSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func newJWTEngine(opts JWTOptions) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(JWT(opts))
+	r.GET("/me", func(c *gin.Context) {
+		key := opts.ContextKey
+		if key == "" {
+			key = JWTClaimsKey
+		}
+		claims := MustGetVar[jwt.MapClaims](c, key)
+		sub, _ := claims.GetSubject()
+		c.String(http.StatusOK, sub)
+	})
+	return r
+}
+
+func doJWTRequest(r *gin.Engine, header, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	if value != "" {
+		req.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestJWT(t *testing.T) {
+	r := newJWTEngine(JWTOptions{Secret: testJWTSecret})
+	valid := signHS256(t, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+
+	t.Run("有效 token", func(t *testing.T) {
+		w := doJWTRequest(r, "Authorization", "Bearer "+valid)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "alice", w.Body.String())
+
+		// 前缀不区分大小写
+		w = doJWTRequest(r, "Authorization", "bearer "+valid)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("过期 token", func(t *testing.T) {
+		expired := signHS256(t, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Minute).Unix()})
+		w := doJWTRequest(r, "Authorization", "Bearer "+expired)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "unauthorized")
+	})
+
+	t.Run("签名被篡改", func(t *testing.T) {
+		parts := strings.Split(valid, ".")
+		payload, _ := json.Marshal(jwt.MapClaims{"sub": "mallory", "exp": time.Now().Add(time.Hour).Unix()})
+		tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer "+tampered).Code)
+
+		other, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}).SignedString([]byte("other"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer "+other).Code)
+	})
+
+	t.Run("缺少或格式错误", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer ").Code)
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Token "+valid).Code)
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer not-a-jwt").Code)
+	})
+
+	t.Run("缺少 exp", func(t *testing.T) {
+		noExp := signHS256(t, jwt.MapClaims{"sub": "alice"})
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer "+noExp).Code)
+	})
+
+	t.Run("拒绝 none 算法", func(t *testing.T) {
+		none, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}).
+			SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer "+none).Code)
+	})
+}
+
+func TestJWT_CustomHeaderAndPrefix(t *testing.T) {
+	r := newJWTEngine(JWTOptions{
+		Secret:     testJWTSecret,
+		Header:     "X-Token",
+		Prefix:     "-",
+		ContextKey: "claims",
+		Issuer:     "gateway",
+	})
+
+	token := signHS256(t, jwt.MapClaims{"sub": "bob", "iss": "gateway", "exp": time.Now().Add(time.Hour).Unix()})
+	w := doJWTRequest(r, "X-Token", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bob", w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer "+token).Code)
+
+	wrongIss := signHS256(t, jwt.MapClaims{"sub": "bob", "iss": "other", "exp": time.Now().Add(time.Hour).Unix()})
+	assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "X-Token", wrongIss).Code)
+}
+
+func TestJWT_JWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	r := newJWTEngine(JWTOptions{JWKSURL: srv.URL})
+	sign := func(kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "carol", "exp": time.Now().Add(time.Hour).Unix()})
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		require.NoError(t, err)
+		return s
+	}
+
+	w := doJWTRequest(r, "Authorization", "Bearer "+sign("k1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "carol", w.Body.String())
+	assert.Equal(t, http.StatusOK, doJWTRequest(r, "Authorization", "Bearer "+sign("k1")).Code)
+	// 公钥被缓存
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// 未知 kid
+	assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer "+sign("k2")).Code)
+
+	// HMAC token 不能用于 JWKS 校验
+	hs := signHS256(t, jwt.MapClaims{"sub": "carol", "exp": time.Now().Add(time.Hour).Unix()})
+	assert.Equal(t, http.StatusUnauthorized, doJWTRequest(r, "Authorization", "Bearer "+hs).Code)
+}
+
+// newTestJWKSServer 返回包含一个 RSA 公钥（kid 为 k1）的 JWKS 服务，failing 为 true 时返回 503。
+func newTestJWKSServer(t *testing.T, fetches *atomic.Int32, failing *atomic.Bool, block chan struct{}) *httptest.Server {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if block != nil {
+			<-block
+		}
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestJWKS_RefreshFailure(t *testing.T) {
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	srv := newTestJWKSServer(t, &fetches, &failing, nil)
+
+	now := time.Unix(1700000000, 0)
+	ks := newJWKS(srv.URL, time.Minute)
+	ks.now = func() time.Time { return now }
+	token := &jwt.Token{Header: map[string]any{"kid": "k1"}}
+
+	key, err := ks.keyFunc(token)
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// 刷新时 JWKS 服务故障：继续使用缓存的公钥
+	failing.Store(true)
+	now = now.Add(2 * time.Minute)
+	cached, err := ks.keyFunc(token)
+	require.NoError(t, err)
+	assert.Same(t, key, cached)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// 失败后退避，间隔内不再重复拉取
+	for i := 0; i < 5; i++ {
+		_, err = ks.keyFunc(token)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// 退避结束后重试，仍失败则继续使用缓存
+	now = now.Add(jwksMinRefresh)
+	_, err = ks.keyFunc(token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), fetches.Load())
+
+	// 服务恢复后更新公钥，之后在刷新间隔内使用缓存
+	failing.Store(false)
+	now = now.Add(jwksMinRefresh)
+	_, err = ks.keyFunc(token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), fetches.Load())
+	now = now.Add(jwksMinRefresh)
+	_, err = ks.keyFunc(token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), fetches.Load())
+}
+
+func TestJWKS_InitialFetchFailure(t *testing.T) {
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	srv := newTestJWKSServer(t, &fetches, &failing, nil)
+
+	now := time.Unix(1700000000, 0)
+	ks := newJWKS(srv.URL, time.Minute)
+	ks.now = func() time.Time { return now }
+	token := &jwt.Token{Header: map[string]any{"kid": "k1"}}
+
+	// 没有可用的缓存公钥时返回拉取错误，退避期间不重复拉取
+	_, err := ks.keyFunc(token)
+	assert.ErrorContains(t, err, "unexpected status 503")
+	_, err = ks.keyFunc(token)
+	assert.ErrorContains(t, err, "unexpected status 503")
+	assert.Equal(t, int32(1), fetches.Load())
+
+	failing.Store(false)
+	now = now.Add(jwksMinRefresh)
+	_, err = ks.keyFunc(token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestJWKS_ConcurrentFetch(t *testing.T) {
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	block := make(chan struct{})
+	srv := newTestJWKSServer(t, &fetches, &failing, block)
+
+	ks := newJWKS(srv.URL, time.Minute)
+	token := &jwt.Token{Header: map[string]any{"kid": "k1"}}
+
+	const n = 10
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := ks.keyFunc(token)
+			errs <- err
+		}()
+	}
+
+	// 拉取进行中时锁已释放，其余请求等待同一次拉取
+	require.Eventually(t, func() bool { return fetches.Load() == 1 }, 5*time.Second, time.Millisecond)
+	close(block)
+	for i := 0; i < n; i++ {
+		assert.NoError(t, <-errs)
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestJWT_InvalidOptions(t *testing.T) {
+	assert.Panics(t, func() { JWT(JWTOptions{}) })
+}
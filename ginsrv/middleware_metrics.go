@@ -0,0 +1,154 @@
+package ginsrv
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// defaultMetricsPath 默认 metrics 路由
+const defaultMetricsPath = "/metrics"
+
+// unmatchedRoute 未匹配到路由（如 404）时使用的 path 标签，避免原始 URL 导致标签基数爆炸
+const unmatchedRoute = "unmatched"
+
+// MetricsConfig Prometheus metrics 路由配置，默认关闭
+type MetricsConfig struct {
+	// Enabled 是否注册 metrics 路由，默认 false
+	Enabled bool `yaml:"enabled"`
+	// Path 路由地址，默认 /metrics
+	Path string `yaml:"path"`
+}
+
+// metricsOptions Metrics 中间件配置
+type metricsOptions struct {
+	registerer prometheus.Registerer
+	namespace  string
+	buckets    []float64
+}
+
+// MetricsOption Metrics 中间件配置选项
+type MetricsOption func(*metricsOptions)
+
+// WithMetricsRegisterer 设置指标注册器，默认 prometheus.DefaultRegisterer
+func WithMetricsRegisterer(reg prometheus.Registerer) MetricsOption {
+	return func(o *metricsOptions) {
+		o.registerer = reg
+	}
+}
+
+// WithMetricsNamespace 设置指标名前缀，如 "app" 生成 app_http_requests_total
+func WithMetricsNamespace(namespace string) MetricsOption {
+	return func(o *metricsOptions) {
+		o.namespace = namespace
+	}
+}
+
+// WithMetricsBuckets 设置请求耗时直方图的桶，默认 prometheus.DefBuckets
+func WithMetricsBuckets(buckets []float64) MetricsOption {
+	return func(o *metricsOptions) {
+		o.buckets = buckets
+	}
+}
+
+// Metrics 创建 Prometheus RED 指标中间件。
+//
+// 记录以下指标，path 标签使用路由模板（如 /users/:id），未匹配路由时为 "unmatched"：
+//   - http_requests_total{method,path,status}：请求总数
+//   - http_request_duration_seconds{method,path,status}：请求耗时直方图
+//   - http_requests_in_flight{method,path}：处理中的请求数
+//
+// 多次调用时复用已注册的指标；指标注册冲突（同名但定义不同）时 panic。
+// metrics 路由通过配置 metrics.enabled 注册。
+//
+// 示例：
+//
+//	engine.Use(ginsrv.Metrics())
+func Metrics(opts ...MetricsOption) gin.HandlerFunc {
+	o := &metricsOptions{
+		registerer: prometheus.DefaultRegisterer,
+		buckets:    prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	requests := registerCollector(o.registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: o.namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests.",
+	}, []string{"method", "path", "status"}))
+	duration := registerCollector(o.registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: o.namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency in seconds.",
+		Buckets:   o.buckets,
+	}, []string{"method", "path", "status"}))
+	inFlight := registerCollector(o.registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: o.namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	}, []string{"method", "path"}))
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		method := c.Request.Method
+		path := c.FullPath()
+		if path == "" {
+			path = unmatchedRoute
+		}
+
+		gauge := inFlight.WithLabelValues(method, path)
+		gauge.Inc()
+		defer gauge.Dec()
+
+		c.Next()
+
+		status := strconv.Itoa(c.Writer.Status())
+		requests.WithLabelValues(method, path, status).Inc()
+		duration.WithLabelValues(method, path, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// registerCollector 注册指标，已注册时返回已存在的指标
+func registerCollector[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+// registerMetricsRoutes 根据配置注册 metrics 路由，只注册一次
+//
+// 输出 WithMetricsGatherer 设置的指标来源；业务已注册同路径的 GET 路由时跳过，避免 gin 因重复路由 panic。
+func (s *GinService) registerMetricsRoutes() {
+	s.metricsOnce.Do(func() {
+		cfg := s.config.Metrics
+		if !cfg.Enabled {
+			return
+		}
+		path := cfg.Path
+		if path == "" {
+			path = defaultMetricsPath
+		}
+		if s.hasRoute(http.MethodGet, path) {
+			return
+		}
+		gatherer := s.metricsGatherer
+		if gatherer == nil {
+			gatherer = prometheus.DefaultGatherer
+		}
+		s.engine.GET(path, gin.WrapH(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})))
+	})
+}
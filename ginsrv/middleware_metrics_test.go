@@ -0,0 +1,154 @@
+package ginsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findMetric 按指标名和标签查找指标
+func findMetric(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	next:
+		for _, m := range family.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if v, ok := labels[lp.GetName()]; ok && v != lp.GetValue() {
+					continue next
+				}
+			}
+			return m
+		}
+	}
+	return nil
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+
+	r := gin.New()
+	r.Use(Metrics(WithMetricsRegisterer(reg)))
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, c.Param("id"))
+	})
+	r.POST("/users", func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+
+	do := func(method, path string) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	}
+	do(http.MethodGet, "/users/1")
+	do(http.MethodGet, "/users/2")
+	do(http.MethodPost, "/users")
+	do(http.MethodGet, "/not/found")
+
+	// path 使用路由模板
+	counter := findMetric(t, reg, "http_requests_total", map[string]string{"method": "GET", "path": "/users/:id", "status": "200"})
+	require.NotNil(t, counter)
+	assert.Equal(t, float64(2), counter.GetCounter().GetValue())
+
+	counter = findMetric(t, reg, "http_requests_total", map[string]string{"method": "POST", "path": "/users", "status": "400"})
+	require.NotNil(t, counter)
+	assert.Equal(t, float64(1), counter.GetCounter().GetValue())
+
+	counter = findMetric(t, reg, "http_requests_total", map[string]string{"path": unmatchedRoute, "status": "404"})
+	require.NotNil(t, counter)
+	assert.Equal(t, float64(1), counter.GetCounter().GetValue())
+	assert.Nil(t, findMetric(t, reg, "http_requests_total", map[string]string{"path": "/users/1"}))
+
+	histogram := findMetric(t, reg, "http_request_duration_seconds", map[string]string{"method": "GET", "path": "/users/:id", "status": "200"})
+	require.NotNil(t, histogram)
+	assert.Equal(t, uint64(2), histogram.GetHistogram().GetSampleCount())
+
+	gauge := findMetric(t, reg, "http_requests_in_flight", map[string]string{"method": "GET", "path": "/users/:id"})
+	require.NotNil(t, gauge)
+	assert.Equal(t, float64(0), gauge.GetGauge().GetValue())
+}
+
+func TestMetrics_InFlightAndReuse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+
+	r := gin.New()
+	// 重复创建时复用已注册的指标
+	m := Metrics(WithMetricsRegisterer(reg), WithMetricsNamespace("app"))
+	require.NotPanics(t, func() { Metrics(WithMetricsRegisterer(reg), WithMetricsNamespace("app")) })
+
+	var inFlight float64
+	r.Use(m)
+	r.GET("/slow", func(c *gin.Context) {
+		inFlight = findMetric(t, reg, "app_http_requests_in_flight", map[string]string{"path": "/slow"}).GetGauge().GetValue()
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, float64(1), inFlight)
+	assert.Equal(t, 1, testutil.CollectAndCount(reg, "app_http_requests_total"))
+}
+
+func TestGinService_MetricsRoute(t *testing.T) {
+	t.Run("默认关闭", func(t *testing.T) {
+		service := New()
+		service.init()
+		service.registerMetricsRoutes()
+
+		w := httptest.NewRecorder()
+		service.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("开启", func(t *testing.T) {
+		service := New()
+		service.init()
+		service.config.Metrics = MetricsConfig{Enabled: true, Path: "/internal/metrics"}
+		service.registerMetricsRoutes()
+
+		w := httptest.NewRecorder()
+		service.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/internal/metrics", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "go_goroutines")
+	})
+
+	t.Run("自定义指标来源", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		service := New(WithMetricsGatherer(reg))
+		service.init()
+		service.config.Metrics = MetricsConfig{Enabled: true}
+		service.Engine().Use(Metrics(WithMetricsRegisterer(reg), WithMetricsNamespace("custom")))
+		service.Engine().GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+		service.registerMetricsRoutes()
+
+		service.Engine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+		w := httptest.NewRecorder()
+		service.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `custom_http_requests_total{method="GET",path="/users",status="200"} 1`)
+		assert.NotContains(t, w.Body.String(), "go_goroutines")
+	})
+
+	t.Run("路径已被业务注册", func(t *testing.T) {
+		service := New()
+		service.init()
+		service.config.Metrics = MetricsConfig{Enabled: true}
+		service.Engine().GET("/metrics", func(c *gin.Context) { c.String(http.StatusOK, "custom") })
+
+		assert.NotPanics(t, service.registerMetricsRoutes)
+		w := httptest.NewRecorder()
+		service.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, "custom", w.Body.String())
+	})
+}
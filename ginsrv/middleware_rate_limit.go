@@ -0,0 +1,131 @@
+package ginsrv
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
+)
+
+// ErrRateLimited 请求超过限流阈值时返回的错误（HTTP 429）。
+var ErrRateLimited = errcode.New(1014290001, "too many requests: rate limit exceeded")
+
+// RateLimitOptions 限流配置。
+type RateLimitOptions struct {
+	// Rate 每秒补充的令牌数，默认 10
+	Rate float64
+	// Burst 令牌桶容量，即允许的突发请求数，默认为 Rate 向上取整
+	Burst int
+	// KeyFunc 限流 key，默认使用客户端 IP
+	KeyFunc func(c *gin.Context) string
+	// IdleTTL 令牌桶空闲超过该时长后被清理，默认 10 分钟
+	IdleTTL time.Duration
+}
+
+// RateLimit 创建基于内存令牌桶的限流中间件。
+//
+// 每个 key 拥有独立的令牌桶，超过限制时通过 ginresp 返回 429 并设置 Retry-After 响应头。
+// 每次调用都会创建独立的限流器，可为不同路由设置不同的限制。
+//
+// 示例：
+//
+//	engine.POST("/login", ginsrv.RateLimit(ginsrv.RateLimitOptions{Rate: 1, Burst: 5}), loginHandler)
+func RateLimit(opts RateLimitOptions) gin.HandlerFunc {
+	return newRateLimiter(opts, time.Now).middleware()
+}
+
+// tokenBucket 单个 key 的令牌桶。
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter 限流器内部实现。
+type rateLimiter struct {
+	opts RateLimitOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(opts RateLimitOptions, now func() time.Time) *rateLimiter {
+	if opts.Rate <= 0 {
+		opts.Rate = 10
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = int(math.Ceil(opts.Rate))
+	}
+	if opts.KeyFunc == nil {
+		opts.KeyFunc = getClientIP
+	}
+	if opts.IdleTTL <= 0 {
+		opts.IdleTTL = 10 * time.Minute
+	}
+	return &rateLimiter{
+		opts:      opts,
+		now:       now,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: now(),
+	}
+}
+
+func (rl *rateLimiter) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		retryAfter, ok := rl.allow(rl.opts.KeyFunc(c))
+		if !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			ginresp.AbortErr(c, ErrRateLimited, nil)
+			return
+		}
+		c.Next()
+	}
+}
+
+// allow 尝试为 key 获取一个令牌，失败时返回距离下一个令牌可用的等待时间。
+func (rl *rateLimiter) allow(key string) (time.Duration, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(rl.opts.Burst), last: now}
+		rl.buckets[key] = b
+	} else if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(rl.opts.Burst), b.tokens+elapsed.Seconds()*rl.opts.Rate)
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	wait := time.Duration((1 - b.tokens) / rl.opts.Rate * float64(time.Second))
+	return wait, false
+}
+
+// sweep 清理空闲超过 IdleTTL 且已补满的令牌桶，删除不影响限流结果。
+func (rl *rateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.opts.IdleTTL {
+		return
+	}
+	rl.lastSweep = now
+	for key, b := range rl.buckets {
+		idle := now.Sub(b.last)
+		if idle >= rl.opts.IdleTTL && b.tokens+idle.Seconds()*rl.opts.Rate >= float64(rl.opts.Burst) {
+			delete(rl.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ginsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit_TokenBucket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	rl := newRateLimiter(RateLimitOptions{Rate: 1, Burst: 3}, func() time.Time { return now })
+
+	r := gin.New()
+	r.Use(rl.middleware())
+	r.GET("/api", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	do := func(remoteAddr string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api", nil)
+		req.RemoteAddr = remoteAddr
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 桶容量内的请求全部通过
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, do("10.0.0.1:1234").Code)
+	}
+
+	// 第 N+1 个请求被拒绝
+	w := do("10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "rate limit exceeded")
+
+	// 不同 IP 独立计数
+	assert.Equal(t, http.StatusOK, do("10.0.0.2:1234").Code)
+
+	// 补充令牌后恢复
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.1:1234").Code)
+}
+
+func TestRateLimit_RetryAfter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rl := newRateLimiter(RateLimitOptions{Rate: 0.2, Burst: 1}, func() time.Time { return now })
+
+	_, ok := rl.allow("k")
+	assert.True(t, ok)
+
+	wait, ok := rl.allow("k")
+	assert.False(t, ok)
+	assert.Equal(t, 5*time.Second, wait)
+
+	now = now.Add(2 * time.Second)
+	wait, ok = rl.allow("k")
+	assert.False(t, ok)
+	assert.InDelta(t, float64(3*time.Second), float64(wait), float64(time.Millisecond))
+}
+
+func TestRateLimit_KeyFuncAndPerRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	byUser := func(c *gin.Context) string { return c.GetHeader("X-User-ID") }
+
+	r := gin.New()
+	r.GET("/strict", RateLimit(RateLimitOptions{Rate: 1, Burst: 1, KeyFunc: byUser}), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	r.GET("/loose", RateLimit(RateLimitOptions{Rate: 1, Burst: 5, KeyFunc: byUser}), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	do := func(path, user string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-User-ID", user)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do("/strict", "alice"))
+	assert.Equal(t, http.StatusTooManyRequests, do("/strict", "alice"))
+	assert.Equal(t, http.StatusOK, do("/strict", "bob"))
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, do("/loose", "alice"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, do("/loose", "alice"))
+}
+
+func TestRateLimit_Sweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rl := newRateLimiter(RateLimitOptions{Rate: 1, Burst: 2, IdleTTL: time.Minute}, func() time.Time { return now })
+
+	rl.allow("a")
+	rl.allow("b")
+	assert.Len(t, rl.buckets, 2)
+
+	now = now.Add(30 * time.Second)
+	rl.allow("b")
+
+	// a 空闲超过 IdleTTL 被清理，b 仍在使用
+	now = now.Add(40 * time.Second)
+	rl.allow("c")
+	assert.NotContains(t, rl.buckets, "a")
+	assert.Contains(t, rl.buckets, "b")
+	assert.Contains(t, rl.buckets, "c")
+}
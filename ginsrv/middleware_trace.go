@@ -2,6 +2,8 @@ package ginsrv
 
 import (
 	"context"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -9,19 +11,62 @@ import (
 
 const TraceIDKey = "trace_id"
 
-func TraceMiddleware(traceKey string) gin.HandlerFunc {
-	if traceKey == "" {
-		traceKey = "X-Request-ID"
+// DefaultTraceHeader 默认的 trace id 请求头。
+const DefaultTraceHeader = "X-Request-ID"
+
+// traceparentHeader W3C Trace Context 请求头，值格式为 version-traceid-parentid-flags。
+const traceparentHeader = "traceparent"
+
+// traceGeneratedKey gin context 中标记 trace id 为自动生成（未从请求头读取）的键，
+// 值为写回 trace id 的响应头名称。
+const traceGeneratedKey = "trace_id_generated"
+
+// TraceMiddleware 创建链路追踪中间件。
+//
+// traceKeys 为按优先级排列的请求头名称，使用第一个非空的值作为 trace id，
+// 均为空时自动生成；响应头始终写回第一个（主）请求头，未指定时为 X-Request-ID。
+// traceparent 请求头会提取其中的 trace-id 部分。
+// 前置中间件（如 GinService 自动注册的追踪中间件）从请求头读取到 trace id 时直接复用；
+// 前置中间件自动生成的 trace id 仍会按 traceKeys 读取请求头，读取到时替换，否则复用生成的值。
+//
+// 示例：
+//
+//	TraceMiddleware("X-Request-ID", "X-Trace-Id", "traceparent")
+func TraceMiddleware(traceKeys ...string) gin.HandlerFunc {
+	keys := make([]string, 0, len(traceKeys))
+	for _, key := range traceKeys {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		keys = append(keys, DefaultTraceHeader)
 	}
+	traceKey := keys[0]
 
 	return func(c *gin.Context) {
-		traceID := c.GetHeader(traceKey)
+		traceID := GetTraceID(c)
+		generatedHeader := c.GetString(traceGeneratedKey)
+		if traceID == "" || generatedHeader != "" {
+			for _, key := range keys {
+				if v := headerTraceID(c, key); v != "" {
+					traceID = v
+					break
+				}
+			}
+		}
 		if traceID == "" {
 			traceID = uuid.NewString()
+			generatedHeader = traceKey
+		} else if generatedHeader != "" && traceID != GetTraceID(c) {
+			// 替换前置中间件生成的 trace id，同步更新其写回的响应头
+			c.Writer.Header().Set(generatedHeader, traceID)
+			generatedHeader = ""
 		}
 
 		// gin context
 		c.Set(TraceIDKey, traceID)
+		c.Set(traceGeneratedKey, generatedHeader)
 
 		// request context（关键）
 		ctx := context.WithValue(c.Request.Context(), TraceIDKey, traceID)
@@ -34,6 +79,19 @@ func TraceMiddleware(traceKey string) gin.HandlerFunc {
 	}
 }
 
+// headerTraceID 从指定请求头读取 trace id。
+func headerTraceID(c *gin.Context, key string) string {
+	v := c.GetHeader(key)
+	if v == "" || http.CanonicalHeaderKey(key) != http.CanonicalHeaderKey(traceparentHeader) {
+		return v
+	}
+	parts := strings.Split(v, "-")
+	if len(parts) < 4 || len(parts[1]) != 32 {
+		return ""
+	}
+	return parts[1]
+}
+
 func GetTraceID(c *gin.Context) string {
 	v, ok := c.Get(TraceIDKey)
 	if !ok {
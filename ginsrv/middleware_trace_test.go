@@ -173,3 +173,129 @@ func TestTraceMiddleware_DifferentRequests(t *testing.T) {
 	assert.NotEmpty(t, w2.Header().Get("X-Request-ID"))
 	assert.NotEqual(t, w1.Header().Get("X-Request-ID"), w2.Header().Get("X-Request-ID"))
 }
+
+func TestTraceMiddleware_MultipleHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name: "主请求头优先",
+			headers: map[string]string{
+				"X-Request-ID": "request-id",
+				"X-Trace-Id":   "trace-id",
+				"traceparent":  traceparent,
+			},
+			expected: "request-id",
+		},
+		{
+			name: "第二个请求头",
+			headers: map[string]string{
+				"X-Trace-Id":  "trace-id",
+				"traceparent": traceparent,
+			},
+			expected: "trace-id",
+		},
+		{
+			name: "traceparent 提取 trace-id",
+			headers: map[string]string{
+				"traceparent": traceparent,
+			},
+			expected: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "traceparent 格式错误时生成",
+			headers: map[string]string{
+				"traceparent": "invalid",
+			},
+		},
+		{
+			name:    "均不存在时生成",
+			headers: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(TraceMiddleware("X-Request-ID", "X-Trace-Id", "traceparent"))
+			r.GET("/test", func(c *gin.Context) {
+				c.String(200, GetTraceID(c))
+			})
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, 200, w.Code)
+			traceID := w.Body.String()
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, traceID)
+			} else {
+				assert.NotEmpty(t, traceID)
+				assert.NotEqual(t, "invalid", traceID)
+			}
+			// 始终写回主请求头
+			assert.Equal(t, traceID, w.Header().Get("X-Request-ID"))
+			assert.Empty(t, w.Header().Get("X-Trace-Id"))
+		})
+	}
+}
+
+func TestTraceMiddleware_ReuseExistingTraceID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(TraceMiddleware("X-Trace-Id"), TraceMiddleware("X-Request-ID"))
+	r.GET("/test", func(c *gin.Context) {
+		c.String(200, GetTraceID(c))
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// 重复注册时复用前置中间件生成的 trace id
+	traceID := w.Header().Get("X-Trace-Id")
+	assert.NotEmpty(t, traceID)
+	assert.Equal(t, traceID, w.Header().Get("X-Request-ID"))
+	assert.Equal(t, traceID, w.Body.String())
+}
+
+func TestTraceMiddleware_ReplaceGeneratedTraceID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(TraceMiddleware("X-Request-ID"), TraceMiddleware("X-Request-ID", "X-Trace-Id", "traceparent"))
+	r.GET("/test", func(c *gin.Context) {
+		c.String(200, GetTraceID(c))
+	})
+
+	t.Run("后置中间件读取到请求头时替换生成的 trace id", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Body.String())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Header().Get("X-Request-ID"))
+	})
+
+	t.Run("前置中间件读取的 trace id 不被替换", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Request-ID", "request-id")
+		req.Header.Set("X-Trace-Id", "trace-id")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, "request-id", w.Body.String())
+		assert.Equal(t, "request-id", w.Header().Get("X-Request-ID"))
+	})
+}
@@ -0,0 +1,53 @@
+package ginsrv
+
+import (
+	"net/http/pprof"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultPprofPrefix 默认 pprof 路由前缀
+const defaultPprofPrefix = "/debug/pprof"
+
+// PprofConfig pprof 配置，默认关闭
+type PprofConfig struct {
+	// Enabled 是否注册 pprof 路由，默认 false
+	Enabled bool `yaml:"enabled"`
+	// Prefix 路由前缀，默认 /debug/pprof
+	Prefix string `yaml:"prefix"`
+	// Username、Password 均不为空时启用 Basic Auth 保护
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// registerPprofRoutes 根据配置注册 pprof 路由，只注册一次
+func (s *GinService) registerPprofRoutes() {
+	s.pprofOnce.Do(func() {
+		cfg := s.config.Pprof
+		if !cfg.Enabled {
+			return
+		}
+		prefix := strings.TrimSuffix(cfg.Prefix, "/")
+		if prefix == "" {
+			prefix = defaultPprofPrefix
+		}
+
+		var handlers []gin.HandlerFunc
+		if cfg.Username != "" && cfg.Password != "" {
+			handlers = append(handlers, gin.BasicAuth(gin.Accounts{cfg.Username: cfg.Password}))
+		}
+		g := s.engine.Group(prefix, handlers...)
+
+		g.GET("/", gin.WrapF(pprof.Index))
+		g.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		g.GET("/profile", gin.WrapF(pprof.Profile))
+		g.GET("/symbol", gin.WrapF(pprof.Symbol))
+		g.POST("/symbol", gin.WrapF(pprof.Symbol))
+		g.GET("/trace", gin.WrapF(pprof.Trace))
+		// pprof.Index 依赖固定的 /debug/pprof/ 前缀解析 profile 名称，这里单独注册
+		for _, name := range []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"} {
+			g.GET("/"+name, gin.WrapH(pprof.Handler(name)))
+		}
+	})
+}
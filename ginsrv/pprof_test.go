@@ -0,0 +1,76 @@
+package ginsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPprofService(t *testing.T, settings map[string]any) *GinService {
+	t.Helper()
+	service := New()
+	service.init()
+
+	v := viper.New()
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+	require.NoError(t, v.Unmarshal(service.config, withYamlTag))
+	service.registerPprofRoutes()
+	return service
+}
+
+func doPprofRequest(service *GinService, path string, auth ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if len(auth) == 2 {
+		req.SetBasicAuth(auth[0], auth[1])
+	}
+	w := httptest.NewRecorder()
+	service.Engine().ServeHTTP(w, req)
+	return w
+}
+
+func TestGinService_Pprof(t *testing.T) {
+	t.Run("默认关闭", func(t *testing.T) {
+		service := newPprofService(t, nil)
+		assert.Equal(t, http.StatusNotFound, doPprofRequest(service, "/debug/pprof/").Code)
+		assert.Equal(t, http.StatusNotFound, doPprofRequest(service, "/debug/pprof/heap").Code)
+	})
+
+	t.Run("开启", func(t *testing.T) {
+		service := newPprofService(t, map[string]any{"pprof.enabled": true})
+
+		w := doPprofRequest(service, "/debug/pprof/")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "goroutine")
+
+		for _, path := range []string{"/debug/pprof/cmdline", "/debug/pprof/heap?debug=1", "/debug/pprof/goroutine?debug=1"} {
+			assert.Equal(t, http.StatusOK, doPprofRequest(service, path).Code, path)
+		}
+	})
+
+	t.Run("自定义前缀", func(t *testing.T) {
+		service := newPprofService(t, map[string]any{
+			"pprof.enabled": true,
+			"pprof.prefix":  "/admin/pprof/",
+		})
+		assert.Equal(t, http.StatusOK, doPprofRequest(service, "/admin/pprof/").Code)
+		assert.Equal(t, http.StatusOK, doPprofRequest(service, "/admin/pprof/heap?debug=1").Code)
+		assert.Equal(t, http.StatusNotFound, doPprofRequest(service, "/debug/pprof/").Code)
+	})
+
+	t.Run("Basic Auth", func(t *testing.T) {
+		service := newPprofService(t, map[string]any{
+			"pprof.enabled":  true,
+			"pprof.username": "admin",
+			"pprof.password": "secret",
+		})
+		assert.Equal(t, http.StatusUnauthorized, doPprofRequest(service, "/debug/pprof/").Code)
+		assert.Equal(t, http.StatusUnauthorized, doPprofRequest(service, "/debug/pprof/", "admin", "wrong").Code)
+		assert.Equal(t, http.StatusOK, doPprofRequest(service, "/debug/pprof/", "admin", "secret").Code)
+	})
+}
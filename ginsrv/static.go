@@ -0,0 +1,136 @@
+package ginsrv
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
+)
+
+// ErrNotFound 未匹配到路由或静态文件时返回的错误（HTTP 404）。
+var ErrNotFound = errcode.New(1014040001, "not found")
+
+// defaultStaticIndex 默认首页文件
+const defaultStaticIndex = "index.html"
+
+// StaticConfig 静态文件配置，dir 为空时不启用
+type StaticConfig struct {
+	// Dir 静态文件目录
+	Dir string `yaml:"dir"`
+	// Prefix URL 前缀，默认 /
+	Prefix string `yaml:"prefix"`
+	// Index 目录首页及 SPA 回退文件，默认 index.html
+	Index string `yaml:"index"`
+	// SPAFallback 为 true 时，前缀下未找到文件的非 API 请求返回首页
+	SPAFallback bool `yaml:"spa_fallback"`
+	// APIPrefixes API 路由前缀，匹配的请求不回退首页，默认 /api
+	APIPrefixes []string `yaml:"api_prefixes"`
+}
+
+// registerStaticRoutes 根据配置注册静态文件处理，只注册一次
+//
+// 静态文件通过全局中间件在未匹配路由时处理（gin 会将全局中间件加入 NoRoute 处理链），
+// 不与已注册的路由冲突，前缀为 / 时也可正常使用，也不会替换业务通过 engine.NoRoute 注册的处理函数；
+// 未找到文件且不满足 SPA 回退条件的请求交给业务的 NoRoute 处理，未注册或其未写入响应时返回 JSON 格式的 404。
+func (s *GinService) registerStaticRoutes() {
+	s.staticOnce.Do(func() {
+		cfg := s.config.Static
+		if cfg.Dir == "" {
+			return
+		}
+		prefix := "/" + strings.Trim(cfg.Prefix, "/")
+		index := cfg.Index
+		if index == "" {
+			index = defaultStaticIndex
+		}
+		apiPrefixes := cfg.APIPrefixes
+		if apiPrefixes == nil {
+			apiPrefixes = []string{"/api"}
+		}
+
+		fsys := http.Dir(cfg.Dir)
+		s.engine.Use(func(c *gin.Context) {
+			// 已匹配路由的请求不处理
+			if c.FullPath() != "" {
+				c.Next()
+				return
+			}
+			method := c.Request.Method
+			rel, ok := trimPathPrefix(c.Request.URL.Path, prefix)
+			if ok && (method == http.MethodGet || method == http.MethodHead) {
+				if serveStaticFile(c, fsys, rel, index) {
+					c.Abort()
+					return
+				}
+				if cfg.SPAFallback && !hasAnyPathPrefix(c.Request.URL.Path, apiPrefixes) &&
+					serveStaticFile(c, fsys, index, index) {
+					c.Abort()
+					return
+				}
+			}
+			c.Next()
+			if !c.Writer.Written() {
+				ginresp.Err(c, ErrNotFound, nil)
+			}
+		})
+	})
+}
+
+// serveStaticFile 返回 fsys 中的文件，目录返回其下的 index 文件，文件不存在时返回 false
+func serveStaticFile(c *gin.Context, fsys http.FileSystem, name, index string) bool {
+	name = path.Clean("/" + name)
+	stat, err := statFile(fsys, name)
+	if err != nil {
+		return false
+	}
+	if stat.IsDir() {
+		name = path.Join(name, index)
+		if stat, err = statFile(fsys, name); err != nil || stat.IsDir() {
+			return false
+		}
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	http.ServeContent(c.Writer, c.Request, stat.Name(), stat.ModTime(), f)
+	return true
+}
+
+// statFile 返回 fsys 中文件的信息
+func statFile(fsys http.FileSystem, name string) (fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// trimPathPrefix 按路径段去除前缀，/static 匹配 /static 与 /static/a，不匹配 /staticx
+func trimPathPrefix(p, prefix string) (string, bool) {
+	if prefix == "/" {
+		return p, true
+	}
+	rest, ok := strings.CutPrefix(p, prefix)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return "", false
+	}
+	return rest, true
+}
+
+// hasAnyPathPrefix 判断路径是否位于任一前缀下
+func hasAnyPathPrefix(p string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if _, ok := trimPathPrefix(p, "/"+strings.Trim(prefix, "/")); ok {
+			return true
+		}
+	}
+	return false
+}
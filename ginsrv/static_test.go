@@ -0,0 +1,130 @@
+package ginsrv
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStaticService(t *testing.T, settings map[string]any) *GinService {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>index</html>"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "assets"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("console.log(1)"), 0o644))
+
+	service := New()
+	service.init()
+	service.Engine().GET("/api/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	v := viper.New()
+	v.Set("static.dir", dir)
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+	require.NoError(t, v.Unmarshal(service.config, withYamlTag))
+	service.registerStaticRoutes()
+	return service
+}
+
+func doStaticRequest(service *GinService, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	service.Engine().ServeHTTP(w, req)
+	return w
+}
+
+func assertJSONNotFound(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "not found", body["message"])
+}
+
+func TestGinService_Static(t *testing.T) {
+	t.Run("返回静态文件", func(t *testing.T) {
+		service := newStaticService(t, nil)
+
+		w := doStaticRequest(service, http.MethodGet, "/assets/app.js")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "console.log(1)", w.Body.String())
+
+		w = doStaticRequest(service, http.MethodGet, "/")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<html>index</html>", w.Body.String())
+
+		// 已注册的路由不受影响
+		assert.Equal(t, http.StatusOK, doStaticRequest(service, http.MethodGet, "/ping").Code)
+		assert.Equal(t, http.StatusOK, doStaticRequest(service, http.MethodGet, "/api/users").Code)
+
+		// 未开启 SPA 回退时不存在的文件返回 JSON 404
+		assertJSONNotFound(t, doStaticRequest(service, http.MethodGet, "/admin/users"))
+	})
+
+	t.Run("自定义前缀", func(t *testing.T) {
+		service := newStaticService(t, map[string]any{"static.prefix": "/static/"})
+
+		w := doStaticRequest(service, http.MethodGet, "/static/assets/app.js")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "console.log(1)", w.Body.String())
+
+		assertJSONNotFound(t, doStaticRequest(service, http.MethodGet, "/assets/app.js"))
+		assertJSONNotFound(t, doStaticRequest(service, http.MethodGet, "/staticx/assets/app.js"))
+	})
+
+	t.Run("SPA 回退", func(t *testing.T) {
+		service := newStaticService(t, map[string]any{"static.spa_fallback": true})
+
+		w := doStaticRequest(service, http.MethodGet, "/admin/users/1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<html>index</html>", w.Body.String())
+
+		// 存在的文件仍按原样返回
+		assert.Equal(t, "console.log(1)", doStaticRequest(service, http.MethodGet, "/assets/app.js").Body.String())
+
+		// API 路径与非 GET 请求仍返回 JSON 404
+		assertJSONNotFound(t, doStaticRequest(service, http.MethodGet, "/api/missing"))
+		assertJSONNotFound(t, doStaticRequest(service, http.MethodPost, "/admin/users"))
+	})
+
+	t.Run("保留业务 NoRoute 处理", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>index</html>"), 0o644))
+
+		service := New()
+		service.init()
+		service.Engine().NoRoute(func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, gin.H{"message": "custom not found"})
+		})
+		service.config.Static = StaticConfig{Dir: dir, SPAFallback: true}
+		service.registerStaticRoutes()
+
+		// 静态文件与 SPA 回退仍然生效
+		assert.Equal(t, "<html>index</html>", doStaticRequest(service, http.MethodGet, "/admin/users").Body.String())
+		// 其余请求交给业务的 NoRoute 处理
+		w := doStaticRequest(service, http.MethodGet, "/api/missing")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"message":"custom not found"}`, w.Body.String())
+		w = doStaticRequest(service, http.MethodPost, "/admin/users")
+		assert.JSONEq(t, `{"message":"custom not found"}`, w.Body.String())
+	})
+
+	t.Run("未配置目录", func(t *testing.T) {
+		service := New()
+		service.init()
+		service.registerStaticRoutes()
+		assert.Equal(t, http.StatusNotFound, doStaticRequest(service, http.MethodGet, "/index.html").Code)
+	})
+}
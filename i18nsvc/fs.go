@@ -0,0 +1,49 @@
+package i18nsvc
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/qq1060656096/mi18n"
+)
+
+// loadI18nFS 加载 fsys 中 dir 目录（含子目录）下的翻译文件。
+// mi18n 只支持从磁盘目录加载，这里将翻译文件复制到临时目录后复用 loadI18n，
+// 保证文件格式与翻译规则与目录模式一致，加载完成后删除临时目录。
+func loadI18nFS(fsys fs.FS, dir, defaultLang string) (*mi18n.I18n, error) {
+	tmpDir, err := os.MkdirTemp("", "i18nsvc-")
+	if err != nil {
+		return nil, fmt.Errorf("load locale files: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isLocaleFile(p) {
+			return nil
+		}
+		buf, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		rel := path.Clean(p)
+		if dir != "." {
+			rel = rel[len(path.Clean(dir))+1:]
+		}
+		dst := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(dst, buf, 0o644)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load locale files: %w", err)
+	}
+
+	return loadI18n(tmpDir, defaultLang)
+}
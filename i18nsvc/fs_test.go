@@ -0,0 +1,60 @@
+package i18nsvc
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"locales/zh.json": {Data: []byte(`[{"id": "welcome", "translation": "欢迎"}, {"id": "greeting", "translation": "你好，{{.Name}}！"}]`)},
+		"locales/en.yaml": {Data: []byte("- id: welcome\n  translation: Welcome\n- id: greeting\n  translation: Hello, {{.Name}}!\n")},
+		"locales/README":  {Data: []byte("not a locale file")},
+		"other/ja.json":   {Data: []byte(`[{"id": "welcome", "translation": "ようこそ"}]`)},
+	}
+}
+
+func TestNewI18nServiceFS(t *testing.T) {
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"default_lang": "en",
+	})
+
+	service := NewI18nServiceFS(newTestFS(), "locales")
+	require.NoError(t, service.Boot(ctx))
+
+	assert.Equal(t, "欢迎", service.T("zh", "welcome", nil))
+	assert.Equal(t, "Welcome", service.T("en", "welcome", nil))
+	assert.Equal(t, "你好，张三！", service.T("zh-CN", "greeting", map[string]any{"Name": "张三"}))
+	assert.Equal(t, "Hello, 张三!", service.TCtx(ctx, "greeting", map[string]any{"Name": "张三"}))
+	// 子目录之外的文件不会被加载
+	assert.Equal(t, "welcome", service.T("ja", "welcome", nil))
+	assert.Equal(t, "missing", service.T("zh", "missing", nil))
+
+	assert.ElementsMatch(t, []string{"zh", "en"}, service.GetSupportedLanguages())
+	assert.NotNil(t, service.I18n())
+	assert.NoError(t, service.Reload())
+	assert.Equal(t, "欢迎", service.T("zh", "welcome", nil))
+}
+
+func TestNewI18nServiceFS_WithoutConfig(t *testing.T) {
+	ctx := createTestContext(t, "other", map[string]interface{}{})
+
+	// 不存在 i18n 配置时使用默认配置
+	service := NewI18nServiceFS(newTestFS(), "locales")
+	require.NoError(t, service.Boot(ctx))
+	assert.Equal(t, "欢迎", service.T("zh", "welcome", nil))
+	assert.Equal(t, "Welcome", service.T("", "welcome", nil))
+}
+
+func TestNewI18nServiceFS_InvalidFile(t *testing.T) {
+	ctx := createTestContext(t, Name, map[string]interface{}{})
+
+	fsys := fstest.MapFS{
+		"zh.json": {Data: []byte(`[{"id": `)},
+	}
+	service := NewI18nServiceFS(fsys, "")
+	assert.Error(t, service.Boot(ctx))
+}
@@ -5,14 +5,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"sync"
+	"text/template"
+	"time"
 
+	"github.com/BurntSushi/toml"
+	"github.com/fsnotify/fsnotify"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/qq1060656096/drugo/kernel"
 	"github.com/qq1060656096/mi18n"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 )
 
 const Name = "i18n"
@@ -22,12 +30,36 @@ var _ kernel.Service = (*I18nService)(nil)
 
 // I18nService 通过 mi18n.I18n 管理多语言翻译。
 type I18nService struct {
-	name        string
-	config      *viper.Viper
-	logger      *zap.Logger
-	i18n        *mi18n.I18n
+	name   string
+	config *viper.Viper
+	logger *zap.Logger
+	i18n   *mi18n.I18n
+	// fsys 不为 nil 时从该文件系统加载翻译，localeDir 为其中的子目录
+	fsys        fs.FS
 	localeDir   string
 	defaultLang string
+	// fallbackLangs 请求语言找不到翻译时依次尝试的语言
+	fallbackLangs []string
+	// logMissing 找不到翻译时是否记录日志
+	logMissing bool
+	// missingHandler 找不到翻译时的回调
+	missingHandler func(lang, key string)
+
+	// languages 支持的语言列表，加载翻译文件时更新
+	languages []string
+	// fileMessages 翻译文件中的消息 ID，按语言存储，用于判断某种语言是否存在翻译
+	fileMessages map[string]map[string]struct{}
+
+	// messages 通过 AddMessage 注册的消息，按语言和键存储，重新加载翻译文件时保留
+	messages map[string]map[string]*template.Template
+
+	// mu 保护 i18n、languages、fileMessages 和 messages，文件监听会在后台协程中重新加载翻译
+	mu sync.RWMutex
+
+	watch         bool
+	watchDebounce time.Duration
+	watcher       *fsnotify.Watcher
+	watchDone     chan struct{}
 
 	once    sync.Once
 	bootErr error
@@ -40,6 +72,27 @@ func NewI18nService() *I18nService {
 	}
 }
 
+// NewI18nServiceFS 创建从 fs.FS 加载翻译的 I18nService，适用于通过 embed.FS 将翻译文件打包进二进制。
+// subdir 为翻译文件在 fsys 中的目录，为空时使用根目录；此模式下不需要配置 locale_dir，
+// 配置不存在时使用默认配置初始化。
+//
+// 示例：
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	app.Register(i18nsvc.NewI18nServiceFS(locales, "locales"))
+func NewI18nServiceFS(fsys fs.FS, subdir string) *I18nService {
+	if subdir == "" {
+		subdir = "."
+	}
+	return &I18nService{
+		name:      Name,
+		fsys:      fsys,
+		localeDir: subdir,
+	}
+}
+
 // Name 返回服务名称。
 func (s *I18nService) Name() string {
 	return s.name
@@ -62,7 +115,11 @@ func (s *I18nService) boot(ctx context.Context) error {
 	cfg, err := k.Config().Get(s.name)
 	if err != nil {
 		// 空配置是允许的，只是不会初始化i18n
-		return nil
+		if s.fsys == nil {
+			return nil
+		}
+		// 文件系统模式下翻译文件已内置，使用默认配置初始化
+		cfg = viper.New()
 	}
 
 	s.config = cfg
@@ -75,31 +132,92 @@ func (s *I18nService) boot(ctx context.Context) error {
 		return fmt.Errorf("build i18n config: %w", err)
 	}
 
-	// 创建mi18n实例
-	s.i18n = mi18n.New(s.localeDir, s.defaultLang)
+	// 加载翻译文件
+	if err := s.load(); err != nil {
+		return err
+	}
 
 	s.logger.Info("i18n service initialized",
 		zap.String("locale_dir", s.localeDir),
 		zap.String("default_lang", s.defaultLang),
+		zap.Strings("fallback_langs", s.fallbackLangs),
 	)
 
+	if s.watch && s.fsys != nil {
+		s.logger.Warn("i18n watch is not supported when loading from fs.FS")
+	} else if s.watch {
+		if err := s.startWatch(); err != nil {
+			return fmt.Errorf("watch locale dir: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// load 加载翻译文件和支持的语言列表。
+func (s *I18nService) load() error {
+	var (
+		i18n *mi18n.I18n
+		err  error
+	)
+	fsys, dir := s.fsys, s.localeDir
+	if fsys != nil {
+		i18n, err = loadI18nFS(fsys, dir, s.defaultLang)
+	} else {
+		i18n, err = loadI18n(s.localeDir, s.defaultLang)
+		fsys, dir = os.DirFS(s.localeDir), "."
+	}
+	if err != nil {
+		return err
+	}
+	fileMessages, err := scanMessageIDs(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	languages, err := scanLanguages(fsys, dir)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("failed to read locale directory", zap.String("dir", s.localeDir), zap.Error(err))
+		}
+		languages = nil
+	}
+
+	s.mu.Lock()
+	s.i18n = i18n
+	s.fileMessages = fileMessages
+	s.languages = languages
+	s.mu.Unlock()
+	return nil
+}
+
+// loadI18n 创建 mi18n 实例，mi18n 解析翻译文件失败时会 panic，这里转换为错误返回。
+func loadI18n(localeDir, defaultLang string) (i18n *mi18n.I18n, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("load locale files: %v", r)
+		}
+	}()
+	return mi18n.New(localeDir, defaultLang), nil
+}
+
 // buildConfig 从 viper 配置构建服务配置。
 func (s *I18nService) buildConfig(ctx context.Context) error {
-	s.localeDir = s.config.GetString("locale_dir")
-	if s.localeDir == "" {
-		return errors.New("locale_dir is required")
-	}
+	// 文件系统模式使用构造时指定的目录
+	if s.fsys == nil {
+		s.localeDir = s.config.GetString("locale_dir")
+		if s.localeDir == "" {
+			return errors.New("locale_dir is required")
+		}
 
-	// 转换为绝对路径
-	if !filepath.IsAbs(s.localeDir) {
-		// 尝试从kernel获取根目录来解析相对路径
-		if k := kernel.MustFromContext(ctx); k.Root() != "" {
-			s.localeDir = filepath.Join(k.Root(), s.localeDir)
+		// 转换为绝对路径
+		if !filepath.IsAbs(s.localeDir) {
+			// 尝试从kernel获取根目录来解析相对路径
+			if k := kernel.MustFromContext(ctx); k.Root() != "" {
+				s.localeDir = filepath.Join(k.Root(), s.localeDir)
+			}
+			// 如果无法获取根目录，则保持相对路径，用户需要确保路径正确
 		}
-		// 如果无法获取根目录，则保持相对路径，用户需要确保路径正确
 	}
 
 	s.defaultLang = s.config.GetString("default_lang")
@@ -107,29 +225,95 @@ func (s *I18nService) buildConfig(ctx context.Context) error {
 		s.defaultLang = "en" // 默认使用英文
 	}
 
+	s.fallbackLangs = s.config.GetStringSlice("fallback_langs")
+	s.logMissing = s.config.GetBool("log_missing")
+
+	s.watch = s.config.GetBool("watch")
+	s.watchDebounce = s.config.GetDuration("watch_debounce")
+	if s.watchDebounce <= 0 {
+		s.watchDebounce = 500 * time.Millisecond
+	}
+
 	return nil
 }
 
 // I18n 返回底层的 mi18n.I18n 实例。
 // 如果 Boot 尚未被调用，则返回 nil。
 func (s *I18nService) I18n() *mi18n.I18n {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.i18n
 }
 
 // T 根据指定的语言和键获取翻译文本。
+// 请求语言找不到翻译时，按 fallback_langs 配置依次尝试，都找不到时返回 key。
 func (s *I18nService) T(lang, key string, data map[string]any) string {
-	if s.i18n == nil {
-		return key
+	msg, ok := s.translate(lang, key, data)
+	if !ok {
+		s.reportMissing(lang, key)
 	}
-	return s.i18n.T(lang, key, data)
+	return msg
 }
 
-// TCtx 从context中获取语言信息并翻译文本。
+// TCtx 从context中获取语言信息并翻译文本，context 中没有语言信息时使用默认语言。
 func (s *I18nService) TCtx(ctx context.Context, key string, data map[string]any) string {
-	if s.i18n == nil {
-		return key
+	return s.T(s.Lang(ctx), key, data)
+}
+
+// translate 按请求语言和回退语言链翻译，返回翻译结果及是否找到翻译。
+// 每种语言先查找 AddMessage 注册的消息，再查找翻译文件。
+//
+// mi18n 在请求语言缺少翻译时会回退到 default_lang，因此先通过 fileMessages 判断
+// 该语言本身是否存在翻译，保证按配置的回退顺序查找，且译文与 key 相同时不会被视为缺失。
+func (s *I18nService) translate(lang, key string, data map[string]any) (string, bool) {
+	s.mu.RLock()
+	i18n, fileMessages := s.i18n, s.fileMessages
+	s.mu.RUnlock()
+	if lang == "" {
+		lang = s.defaultLang
+	}
+
+	for i, l := range append([]string{lang}, s.fallbackLangs...) {
+		if i > 0 && l == lang {
+			continue
+		}
+		if msg, ok := s.lookupMessage(l, key, data); ok {
+			return msg, true
+		}
+		if i18n == nil {
+			continue
+		}
+		if _, ok := fileMessages[normalizeLang(l)][key]; ok {
+			return i18n.T(l, key, data), true
+		}
+	}
+	return key, false
+}
+
+// SetMissingHandler 设置找不到翻译时的回调，可用于统计缺失翻译等自定义指标，nil 表示不回调。
+// 通过回退语言找到翻译时不会触发；仅存在于 default_lang 而未列入 fallback_langs 的键视为缺失，
+// 译文与 key 相同的键不视为缺失。lang 为请求的语言，未指定时为默认语言。
+func (s *I18nService) SetMissingHandler(handler func(lang, key string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.missingHandler = handler
+}
+
+// reportMissing 记录找不到翻译的键，开启 log_missing 配置时记录警告日志，并调用 missingHandler。
+func (s *I18nService) reportMissing(lang, key string) {
+	if lang == "" {
+		lang = s.defaultLang
+	}
+	if s.logMissing && s.logger != nil {
+		s.logger.Warn("i18n translation missing", zap.String("lang", lang), zap.String("key", key))
+	}
+
+	s.mu.RLock()
+	handler := s.missingHandler
+	s.mu.RUnlock()
+	if handler != nil {
+		handler(lang, key)
 	}
-	return s.i18n.TCtx(ctx, key, data)
 }
 
 // WithLang 将语言信息写入context。
@@ -142,20 +326,27 @@ func (s *I18nService) Lang(ctx context.Context) string {
 	return mi18n.Lang(ctx)
 }
 
+// DefaultLang 返回默认语言。
+func (s *I18nService) DefaultLang() string {
+	return s.defaultLang
+}
+
 // GetSupportedLanguages 返回支持的语言列表。
-// 这个方法会扫描locale目录下的所有翻译文件，返回支持的语言代码。
+// 语言列表在加载翻译文件时扫描 locale 目录得到，即翻译文件名去掉扩展名。
 func (s *I18nService) GetSupportedLanguages() []string {
-	if s.i18n == nil || s.localeDir == "" {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.i18n == nil {
 		return []string{}
 	}
+	return append([]string{}, s.languages...)
+}
 
-	// 读取locale目录下的文件
-	entries, err := os.ReadDir(s.localeDir)
+// scanLanguages 扫描 fsys 中 dir 目录下的翻译文件，返回语言代码列表。
+func scanLanguages(fsys fs.FS, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
-		if s.logger != nil {
-			s.logger.Error("failed to read locale directory", zap.String("dir", s.localeDir), zap.Error(err))
-		}
-		return []string{}
+		return nil, err
 	}
 
 	var languages []string
@@ -172,19 +363,60 @@ func (s *I18nService) GetSupportedLanguages() []string {
 			languages = append(languages, lang)
 		}
 	}
+	return languages, nil
+}
 
-	return languages
+// scanMessageIDs 解析 fsys 中 dir 目录（含子目录）下的翻译文件，返回各语言的消息 ID。
+func scanMessageIDs(fsys fs.FS, dir string) (map[string]map[string]struct{}, error) {
+	unmarshalFuncs := map[string]goi18n.UnmarshalFunc{
+		"toml": toml.Unmarshal,
+		"yaml": yaml.Unmarshal,
+		"yml":  yaml.Unmarshal,
+	}
+
+	ids := make(map[string]map[string]struct{})
+	err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isLocaleFile(p) {
+			return nil
+		}
+		buf, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		mf, err := goi18n.ParseMessageFileBytes(buf, path.Base(p), unmarshalFuncs)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", p, err)
+		}
+		lang := normalizeLang(mf.Tag.String())
+		if ids[lang] == nil {
+			ids[lang] = make(map[string]struct{}, len(mf.Messages))
+		}
+		for _, msg := range mf.Messages {
+			ids[lang][msg.ID] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load locale files: %w", err)
+	}
+	return ids, nil
 }
 
 // Reload 重新加载翻译文件。
-// 当翻译文件更新后，可以调用此方法重新加载。
+// 当翻译文件更新后，可以调用此方法重新加载；开启 watch 配置时会自动调用。
+// 翻译文件解析失败时返回错误并保留原有翻译。
 func (s *I18nService) Reload() error {
 	if s.localeDir == "" || s.defaultLang == "" {
 		return errors.New("i18n service not properly initialized")
 	}
 
-	// 重新创建mi18n实例
-	s.i18n = mi18n.New(s.localeDir, s.defaultLang)
+	// 重新加载翻译文件
+	if err := s.load(); err != nil {
+		return err
+	}
 
 	if s.logger != nil {
 		s.logger.Info("i18n service reloaded",
@@ -198,6 +430,7 @@ func (s *I18nService) Reload() error {
 
 // Close 释放国际化服务资源。
 func (s *I18nService) Close(ctx context.Context) error {
+	s.stopWatch()
 	if s.logger != nil {
 		s.logger.Info("i18n service closed")
 	}
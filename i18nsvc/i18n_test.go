@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/qq1060656096/drugo/config"
 	"github.com/qq1060656096/drugo/kernel"
@@ -353,3 +354,112 @@ func TestI18nService_Reload_WithoutInit(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestI18nService_Watch(t *testing.T) {
+	localeDir := t.TempDir()
+	zhFile := filepath.Join(localeDir, "zh.json")
+	require.NoError(t, os.WriteFile(zhFile, []byte(`[{"id": "welcome", "translation": "欢迎"}]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"locale_dir":     localeDir,
+		"default_lang":   "en",
+		"watch":          true,
+		"watch_debounce": "50ms",
+	})
+
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+	defer service.Close(ctx)
+	require.Equal(t, "欢迎", service.T("zh", "welcome", nil))
+
+	// 修改翻译文件，无需手动 Reload
+	require.NoError(t, os.WriteFile(zhFile, []byte(`[{"id": "welcome", "translation": "欢迎回来"}]`), 0644))
+	require.Eventually(t, func() bool {
+		return service.T("zh", "welcome", nil) == "欢迎回来"
+	}, 3*time.Second, 20*time.Millisecond)
+
+	// 新增翻译文件同样生效
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "ja.json"), []byte(`[{"id": "welcome", "translation": "ようこそ"}]`), 0644))
+	require.Eventually(t, func() bool {
+		return service.T("ja", "welcome", nil) == "ようこそ"
+	}, 3*time.Second, 20*time.Millisecond)
+
+	// Close 后监听停止
+	require.NoError(t, service.Close(ctx))
+	require.Nil(t, service.watcher)
+}
+
+func TestI18nService_Reload_InvalidFile(t *testing.T) {
+	localeDir := t.TempDir()
+	zhFile := filepath.Join(localeDir, "zh.json")
+	require.NoError(t, os.WriteFile(zhFile, []byte(`[{"id": "welcome", "translation": "欢迎"}]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{"locale_dir": localeDir})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+
+	// 解析失败时返回错误并保留原有翻译
+	require.NoError(t, os.WriteFile(zhFile, []byte(`[{"id": `), 0644))
+	require.Error(t, service.Reload())
+	require.Equal(t, "欢迎", service.T("zh", "welcome", nil))
+}
+
+func TestI18nService_FallbackLangs(t *testing.T) {
+	localeDir := t.TempDir()
+	files := map[string]string{
+		"zh.json": `[{"id": "welcome", "translation": "欢迎"}]`,
+		"en.json": `[{"id": "welcome", "translation": "Welcome"}, {"id": "goodbye", "translation": "Goodbye, {{.Name}}"}]`,
+		"ja.json": `[{"id": "thanks", "translation": "ありがとう"}]`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(localeDir, name), []byte(content), 0644))
+	}
+
+	t.Run("按回退语言链翻译", func(t *testing.T) {
+		ctx := createTestContext(t, Name, map[string]interface{}{
+			"locale_dir":     localeDir,
+			"default_lang":   "en",
+			"fallback_langs": []string{"ja", "en"},
+		})
+		service := New()
+		require.NoError(t, service.Boot(ctx))
+
+		// 请求语言存在翻译
+		require.Equal(t, "欢迎", service.T("zh", "welcome", nil))
+		// 只存在于回退语言中，按顺序回退
+		require.Equal(t, "ありがとう", service.T("zh", "thanks", nil))
+		require.Equal(t, "Goodbye, 张三", service.T("zh", "goodbye", map[string]any{"Name": "张三"}))
+		require.Equal(t, "Goodbye, 张三", service.TCtx(service.WithLang(ctx, "zh"), "goodbye", map[string]any{"Name": "张三"}))
+		// 所有语言都不存在时返回 key
+		require.Equal(t, "missing", service.T("zh", "missing", nil))
+	})
+
+	t.Run("回退顺序不受默认语言影响", func(t *testing.T) {
+		ctx := createTestContext(t, Name, map[string]interface{}{
+			"locale_dir":     localeDir,
+			"default_lang":   "en",
+			"fallback_langs": []string{"ja", "en"},
+		})
+		service := New()
+		require.NoError(t, service.Boot(ctx))
+		require.NoError(t, service.AddMessage("ja", "welcome", "ようこそ"))
+
+		// welcome 同时存在于 ja（运行时注册）与默认语言 en，按 fallback_langs 先使用 ja
+		require.Equal(t, "ようこそ", service.T("fr", "welcome", nil))
+		require.Equal(t, "ようこそ", service.T("ko", "welcome", nil))
+		// 请求语言存在翻译文件但缺少该键时同样先回退到 ja
+		require.NoError(t, service.AddMessage("ja", "goodbye", "さようなら"))
+		require.Equal(t, "さようなら", service.T("zh", "goodbye", nil))
+	})
+
+	t.Run("未配置回退语言", func(t *testing.T) {
+		ctx := createTestContext(t, Name, map[string]interface{}{
+			"locale_dir":   localeDir,
+			"default_lang": "en",
+		})
+		service := New()
+		require.NoError(t, service.Boot(ctx))
+
+		require.Equal(t, "goodbye", service.T("zh", "goodbye", nil))
+	})
+}
@@ -0,0 +1,61 @@
+package i18nsvc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// AddMessage 在运行时注册一条翻译，注册后立即可被 T、TCtx 使用。
+//
+// 注册的消息优先于翻译文件中的同名翻译，Reload 不会清除；未配置 locale_dir 或 Boot 之前也可使用，
+// 适用于插件注入翻译和测试。translation 支持与翻译文件相同的模板变量，如 "你好，{{.Name}}！"。
+func (s *I18nService) AddMessage(lang, id, translation string) error {
+	lang = normalizeLang(lang)
+	if lang == "" || id == "" {
+		return errors.New("i18n message lang and id are required")
+	}
+
+	tmpl, err := template.New(id).Parse(translation)
+	if err != nil {
+		return fmt.Errorf("parse i18n message %s.%s: %w", lang, id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.messages == nil {
+		s.messages = make(map[string]map[string]*template.Template)
+	}
+	if s.messages[lang] == nil {
+		s.messages[lang] = make(map[string]*template.Template)
+	}
+	s.messages[lang][id] = tmpl
+	return nil
+}
+
+// lookupMessage 查找 AddMessage 注册的消息并渲染模板。
+func (s *I18nService) lookupMessage(lang, key string, data map[string]any) (string, bool) {
+	s.mu.RLock()
+	tmpl, ok := s.messages[normalizeLang(lang)][key]
+	s.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", false
+	}
+	return sb.String(), true
+}
+
+// normalizeLang 将带区域代码的语言（如 "zh-CN"）转换为基本语言代码（如 "zh"），与 mi18n 一致。
+func normalizeLang(lang string) string {
+	for i, c := range lang {
+		if c == '-' {
+			return lang[:i]
+		}
+	}
+	return lang
+}
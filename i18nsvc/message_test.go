@@ -0,0 +1,56 @@
+package i18nsvc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestI18nService_AddMessage(t *testing.T) {
+	localeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "zh.json"), []byte(`[{"id": "welcome", "translation": "欢迎"}]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"locale_dir":   localeDir,
+		"default_lang": "en",
+	})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+
+	// 新增消息
+	require.NoError(t, service.AddMessage("zh", "greeting", "你好，{{.Name}}！"))
+	assert.Equal(t, "你好，张三！", service.T("zh", "greeting", map[string]any{"Name": "张三"}))
+	assert.Equal(t, "你好，张三！", service.T("zh-CN", "greeting", map[string]any{"Name": "张三"}))
+	assert.Equal(t, "greeting", service.T("ja", "greeting", nil))
+
+	// 覆盖翻译文件中已有的键
+	require.NoError(t, service.AddMessage("zh", "welcome", "欢迎光临"))
+	assert.Equal(t, "欢迎光临", service.T("zh", "welcome", nil))
+
+	// 覆盖之前注册的消息
+	require.NoError(t, service.AddMessage("zh", "welcome", "热烈欢迎"))
+	assert.Equal(t, "热烈欢迎", service.TCtx(service.WithLang(ctx, "zh"), "welcome", nil))
+
+	// 重新加载翻译文件后仍然保留
+	require.NoError(t, service.Reload())
+	assert.Equal(t, "热烈欢迎", service.T("zh", "welcome", nil))
+}
+
+func TestI18nService_AddMessage_WithoutLocaleDir(t *testing.T) {
+	service := New()
+
+	require.NoError(t, service.AddMessage("en", "welcome", "Welcome"))
+	assert.Equal(t, "Welcome", service.T("en", "welcome", nil))
+	assert.Equal(t, "welcome", service.T("zh", "welcome", nil))
+}
+
+func TestI18nService_AddMessage_Invalid(t *testing.T) {
+	service := New()
+
+	assert.Error(t, service.AddMessage("", "welcome", "Welcome"))
+	assert.Error(t, service.AddMessage("en", "", "Welcome"))
+	assert.Error(t, service.AddMessage("en", "welcome", "Hello, {{.Name"))
+}
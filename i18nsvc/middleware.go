@@ -0,0 +1,88 @@
+package i18nsvc
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware 创建解析 Accept-Language 请求头的 gin 中间件。
+//
+// 按 q 权重依次匹配 GetSupportedLanguages() 中的语言，先精确匹配（忽略大小写），
+// 再按主语言匹配（如 zh-CN 匹配 zh），都不匹配时使用默认语言。
+// 匹配到的语言通过 WithLang 写入请求的 context，处理器中可直接使用 TCtx 翻译。
+//
+// 示例：
+//
+//	engine.Use(i18nSvc.Middleware())
+func (s *I18nService) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := matchLanguage(c.GetHeader("Accept-Language"), s.GetSupportedLanguages(), s.defaultLang)
+		c.Request = c.Request.WithContext(s.WithLang(c.Request.Context(), lang))
+		c.Next()
+	}
+}
+
+// acceptLanguage Accept-Language 中的单个语言及其权重。
+type acceptLanguage struct {
+	tag string
+	q   float64
+}
+
+// parseAcceptLanguage 解析 Accept-Language 请求头，按 q 值从高到低排序，q 值相同时保持原有顺序。
+// q=0 表示不接受，会被忽略。
+func parseAcceptLanguage(header string) []acceptLanguage {
+	var langs []acceptLanguage
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, acceptLanguage{tag: tag, q: q})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+	return langs
+}
+
+// matchLanguage 根据 Accept-Language 从支持的语言中选出最合适的语言，无匹配时返回 defaultLang。
+func matchLanguage(header string, supported []string, defaultLang string) string {
+	for _, al := range parseAcceptLanguage(header) {
+		if al.tag == "*" {
+			return defaultLang
+		}
+		for _, lang := range supported {
+			if strings.EqualFold(al.tag, lang) {
+				return lang
+			}
+		}
+		base, _, _ := strings.Cut(al.tag, "-")
+		for _, lang := range supported {
+			if strings.EqualFold(base, lang) {
+				return lang
+			}
+		}
+	}
+	return defaultLang
+}
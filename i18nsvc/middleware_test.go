@@ -0,0 +1,76 @@
+package i18nsvc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	supported := []string{"en", "zh", "ja", "pt-BR"}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"精确匹配", "zh", "zh"},
+		{"忽略大小写", "PT-br", "pt-BR"},
+		{"主语言匹配", "zh-CN", "zh"},
+		{"按权重选择", "fr;q=0.9, ja;q=0.8, zh;q=0.5", "ja"},
+		{"权重高者优先", "zh;q=0.3, ja", "ja"},
+		{"q=0 表示不接受", "ja;q=0, zh;q=0.1", "zh"},
+		{"不支持的语言回退默认", "fr, de;q=0.8", "en"},
+		{"通配符", "fr, *;q=0.5", "en"},
+		{"空请求头", "", "en"},
+		{"格式错误", ";q=1, ,", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchLanguage(tt.header, supported, "en"))
+		})
+	}
+}
+
+func TestI18nService_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	localeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "zh.json"), []byte(`[{"id": "welcome", "translation": "欢迎"}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "en.json"), []byte(`[{"id": "welcome", "translation": "Welcome"}]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"locale_dir":   localeDir,
+		"default_lang": "en",
+	})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+
+	r := gin.New()
+	r.Use(service.Middleware())
+	r.GET("/", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		c.String(http.StatusOK, service.Lang(ctx)+":"+service.TCtx(ctx, "welcome", nil))
+	})
+
+	do := func(header string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("Accept-Language", header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "zh:欢迎", do("zh"))
+	assert.Equal(t, "zh:欢迎", do("fr;q=0.9, zh-CN;q=0.8, en;q=0.7"))
+	assert.Equal(t, "en:Welcome", do("fr"))
+	assert.Equal(t, "en:Welcome", do(""))
+}
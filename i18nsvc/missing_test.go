@@ -0,0 +1,97 @@
+package i18nsvc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestI18nService_MissingHandler(t *testing.T) {
+	localeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "zh.json"), []byte(`[{"id": "welcome", "translation": "欢迎"}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "en.json"), []byte(`[{"id": "goodbye", "translation": "Goodbye"}]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"locale_dir":     localeDir,
+		"default_lang":   "en",
+		"fallback_langs": []string{"en"},
+		"log_missing":    true,
+	})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+
+	core, logs := observer.New(zap.WarnLevel)
+	service.logger = zap.New(core)
+
+	type missing struct{ lang, key string }
+	var got []missing
+	service.SetMissingHandler(func(lang, key string) {
+		got = append(got, missing{lang, key})
+	})
+
+	// 存在的键不触发
+	assert.Equal(t, "欢迎", service.T("zh", "welcome", nil))
+	// 通过回退语言找到时不触发
+	assert.Equal(t, "Goodbye", service.T("zh", "goodbye", nil))
+	assert.Empty(t, got)
+	assert.Equal(t, 0, logs.Len())
+
+	// 真正缺失的键触发回调并记录日志
+	assert.Equal(t, "missing", service.T("zh", "missing", nil))
+	assert.Equal(t, "missing", service.TCtx(ctx, "missing", nil))
+	assert.Equal(t, []missing{{"zh", "missing"}, {"en", "missing"}}, got)
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, "i18n translation missing", logs.All()[0].Message)
+	assert.Equal(t, "zh", logs.All()[0].ContextMap()["lang"])
+
+	// 取消回调
+	service.SetMissingHandler(nil)
+	service.T("zh", "missing", nil)
+	assert.Len(t, got, 2)
+}
+
+func TestI18nService_LogMissing_Disabled(t *testing.T) {
+	localeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "zh.json"), []byte(`[{"id": "welcome", "translation": "欢迎"}]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{"locale_dir": localeDir})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+
+	core, logs := observer.New(zap.DebugLevel)
+	service.logger = zap.New(core)
+
+	assert.Equal(t, "missing", service.T("zh", "missing", nil))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestI18nService_MissingHandler_ExistenceCheck(t *testing.T) {
+	localeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "zh.json"), []byte(`[{"id": "OK", "translation": "OK"}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "en.json"), []byte(`[{"id": "goodbye", "translation": "Goodbye"}]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"locale_dir":   localeDir,
+		"default_lang": "en",
+	})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+
+	var got []string
+	service.SetMissingHandler(func(lang, key string) {
+		got = append(got, lang+"."+key)
+	})
+
+	// 译文与 key 相同不视为缺失
+	assert.Equal(t, "OK", service.T("zh", "OK", nil))
+	assert.Empty(t, got)
+
+	// 只存在于默认语言、未配置回退时，请求语言视为缺失
+	assert.Equal(t, "goodbye", service.T("zh", "goodbye", nil))
+	assert.Equal(t, []string{"zh.goodbye"}, got)
+}
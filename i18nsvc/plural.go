@@ -0,0 +1,47 @@
+package i18nsvc
+
+import "maps"
+
+// 复数形式键名后缀，count 为 0、1 时优先使用 _zero、_one，其余及找不到时使用 _other。
+const (
+	pluralZeroSuffix  = "_zero"
+	pluralOneSuffix   = "_one"
+	pluralOtherSuffix = "_other"
+)
+
+// TPlural 按数量选择复数形式并翻译，count 以 Count 变量传入模板。
+//
+// 按 {key}_zero / {key}_one / {key}_other 约定查找翻译：count 为 0 时依次查找 _zero、_other，
+// 为 1 时依次查找 _one、_other，其余查找 _other；均未找到时使用 key 本身的翻译，仍找不到时返回 key。
+//
+// 示例：
+//
+//	// en.json: [{"id": "apple_zero", "translation": "no apples"},
+//	//           {"id": "apple_one", "translation": "one apple"},
+//	//           {"id": "apple_other", "translation": "{{.Count}} apples"}]
+//	svc.TPlural("en", "apple", 5, nil) // "5 apples"
+func (s *I18nService) TPlural(lang, key string, count int, data map[string]any) string {
+	vars := make(map[string]any, len(data)+1)
+	maps.Copy(vars, data)
+	vars["Count"] = count
+
+	for _, id := range pluralKeys(key, count) {
+		if msg, ok := s.translate(lang, id, vars); ok {
+			return msg
+		}
+	}
+	s.reportMissing(lang, key)
+	return key
+}
+
+// pluralKeys 返回按优先级排列的候选键名。
+func pluralKeys(key string, count int) []string {
+	switch count {
+	case 0:
+		return []string{key + pluralZeroSuffix, key + pluralOtherSuffix, key}
+	case 1:
+		return []string{key + pluralOneSuffix, key + pluralOtherSuffix, key}
+	default:
+		return []string{key + pluralOtherSuffix, key}
+	}
+}
@@ -0,0 +1,53 @@
+package i18nsvc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestI18nService_TPlural(t *testing.T) {
+	localeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(localeDir, "en.json"), []byte(`[
+		{"id": "apple_zero", "translation": "{{.Name}} has no apples"},
+		{"id": "apple_one", "translation": "{{.Name}} has one apple"},
+		{"id": "apple_other", "translation": "{{.Name}} has {{.Count}} apples"},
+		{"id": "item_other", "translation": "{{.Count}} items"},
+		{"id": "file", "translation": "file"}
+	]`), 0644))
+
+	ctx := createTestContext(t, Name, map[string]interface{}{
+		"locale_dir":   localeDir,
+		"default_lang": "en",
+	})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+
+	data := map[string]any{"Name": "Tom"}
+	assert.Equal(t, "Tom has no apples", service.TPlural("en", "apple", 0, data))
+	assert.Equal(t, "Tom has one apple", service.TPlural("en", "apple", 1, data))
+	assert.Equal(t, "Tom has 5 apples", service.TPlural("en", "apple", 5, data))
+	assert.NotContains(t, data, "Count")
+
+	// 缺少 _zero、_one 时使用 _other
+	assert.Equal(t, "0 items", service.TPlural("en", "item", 0, nil))
+	assert.Equal(t, "1 items", service.TPlural("en", "item", 1, nil))
+
+	// 没有复数形式时使用 key 本身的翻译
+	assert.Equal(t, "file", service.TPlural("en", "file", 5, nil))
+
+	// 运行时注册的复数形式
+	require.NoError(t, service.AddMessage("en", "msg_one", "one message"))
+	require.NoError(t, service.AddMessage("en", "msg_other", "{{.Count}} messages"))
+	assert.Equal(t, "one message", service.TPlural("en", "msg", 1, nil))
+	assert.Equal(t, "5 messages", service.TPlural("en", "msg", 5, nil))
+
+	// 找不到翻译时返回 key 并回调
+	var missing []string
+	service.SetMissingHandler(func(lang, key string) { missing = append(missing, lang+":"+key) })
+	assert.Equal(t, "unknown", service.TPlural("en", "unknown", 5, nil))
+	assert.Equal(t, []string{"en:unknown"}, missing)
+}
@@ -0,0 +1,99 @@
+package i18nsvc
+
+import (
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// startWatch 监听 localeDir（含子目录），翻译文件变化时去抖后自动调用 Reload。
+func (s *I18nService) startWatch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	err = filepath.WalkDir(s.localeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	s.watcher = watcher
+	s.watchDone = make(chan struct{})
+	go s.watchLoop(watcher, s.watchDone)
+
+	s.logger.Info("i18n watching locale dir",
+		zap.String("locale_dir", s.localeDir),
+		zap.Duration("debounce", s.watchDebounce),
+	)
+	return nil
+}
+
+// watchLoop 处理文件事件，在 watchDebounce 时间内的多次变化只触发一次重新加载。
+func (s *I18nService) watchLoop(watcher *fsnotify.Watcher, done chan struct{}) {
+	defer close(done)
+
+	timer := time.NewTimer(s.watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !isLocaleFile(event.Name) {
+				// 新建子目录时加入监听
+				if event.Has(fsnotify.Create) {
+					_ = watcher.Add(event.Name)
+				}
+				continue
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			timer.Reset(s.watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			s.logger.Error("i18n watcher error", zap.Error(err))
+		case <-timer.C:
+			if err := s.Reload(); err != nil {
+				s.logger.Error("i18n auto reload failed", zap.String("locale_dir", s.localeDir), zap.Error(err))
+			}
+		}
+	}
+}
+
+// stopWatch 停止文件监听并等待监听协程退出。
+func (s *I18nService) stopWatch() {
+	if s.watcher == nil {
+		return
+	}
+	_ = s.watcher.Close()
+	<-s.watchDone
+	s.watcher = nil
+}
+
+// isLocaleFile 判断是否为 mi18n 支持的翻译文件。
+func isLocaleFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".toml", ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
@@ -17,15 +17,20 @@ package ginresp
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/qq1060656096/bizutil/eresp"
 	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/bizutil/qsql"
 )
 
 // TraceIDKey 用于在 Gin Context 中存储 trace ID 的键名常量
 const TraceIDKey = "trace_id"
 
+// CodeValidationFailed 参数校验失败的业务错误码（HTTP 400）。
+const CodeValidationFailed = 1014000001
+
 //
 // ---------- public api ----------
 //
@@ -47,6 +52,42 @@ func OKMsg(c *gin.Context, data any, msg string) {
 	write(c, http.StatusOK, eresp.OKResp(data, msg))
 }
 
+// Created 返回创建成功响应（HTTP 201），响应体与 OK 一致。
+// 参数：
+//   - c: Gin 上下文对象
+//   - data: 新创建的资源数据
+func Created(c *gin.Context, data any) {
+	write(c, http.StatusCreated, eresp.OKResp(data, ""))
+}
+
+// NoContent 返回无内容响应（HTTP 204），不写响应体。
+// 参数：
+//   - c: Gin 上下文对象
+func NoContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+}
+
+// OKList 返回列表成功响应，data 为 {"list": list, "total": total}。
+// 参数：
+//   - c: Gin 上下文对象
+//   - list: 列表数据
+//   - total: 总记录数
+func OKList(c *gin.Context, list any, total int64) {
+	write(c, http.StatusOK, eresp.OKResp(gin.H{"list": list, "total": total}, ""))
+}
+
+// OKPage 返回分页列表成功响应，data 为 {"list": items, "total": total, "page": page, "size": size}。
+// 参数：
+//   - c: Gin 上下文对象
+//   - items: 当前页数据
+//   - total: 总记录数
+//   - page: 当前页码
+//   - size: 每页条数
+func OKPage(c *gin.Context, items any, total int64, page, size int) {
+	write(c, http.StatusOK, eresp.OKResp(gin.H{"list": items, "total": total, "page": page, "size": size}, ""))
+}
+
 // Fail 返回业务错误（固定 200，适合前端业务码判断）。
 // 参数：
 //   - c: Gin 上下文对象
@@ -57,6 +98,26 @@ func Fail(c *gin.Context, code int, msg string, details any) {
 	write(c, httpStatus, eresp.ErrorResp(code, "", msg, details))
 }
 
+// FailStatus 返回业务错误，并由调用方指定 HTTP 状态码。
+// 适用于需要非 2xx 状态码的客户端或监控场景，响应体与 Fail 一致。
+// 参数：
+//   - c: Gin 上下文对象
+//   - httpStatus: HTTP 状态码
+//   - code: 业务错误码，用于前端判断具体错误类型
+//   - msg: 错误消息描述
+//   - details: 错误详情，可为 nil
+func FailStatus(c *gin.Context, httpStatus int, code int, msg string, details any) {
+	write(c, httpStatus, eresp.ErrorResp(code, "", msg, details))
+}
+
+// FailValidators 返回参数校验失败响应（HTTP 400），details 为校验错误列表。
+// 参数：
+//   - c: Gin 上下文对象
+//   - errs: qsql 模板校验错误列表
+func FailValidators(c *gin.Context, errs []*qsql.ValidatorError) {
+	Fail(c, CodeValidationFailed, "validation failed", errs)
+}
+
 // Err 根据 error 自动生成响应。
 // 会自动解析错误类型并设置对应的 HTTP 状态码。
 // 参数：
@@ -68,6 +129,23 @@ func Err(c *gin.Context, err error, details any) {
 	write(c, status, resp)
 }
 
+// Redirect 返回重定向响应。
+// 始终设置 Location 头；对浏览器请求返回标准 HTTP 重定向，
+// 对 XHR/JSON 客户端（X-Requested-With: XMLHttpRequest 或 Accept 包含 application/json）
+// 返回 HTTP 200 的 JSON 响应，data 为 {"location": location}，由前端自行跳转。
+// 参数：
+//   - c: Gin 上下文对象
+//   - status: 重定向状态码，如 302、303
+//   - location: 重定向目标地址
+func Redirect(c *gin.Context, status int, location string) {
+	if !wantsJSON(c) {
+		c.Redirect(status, location)
+		return
+	}
+	c.Header("Location", location)
+	write(c, http.StatusOK, eresp.OKResp(gin.H{"location": location}, ""))
+}
+
 //
 // ---------- abort ----------
 //
@@ -108,7 +186,7 @@ func AbortErr(c *gin.Context, err error, details any) {
 //
 
 // write 内部函数：写入 JSON 响应。
-// 会自动添加 trace ID（如果存在）到响应中。
+// 会自动添加 trace ID（如果存在）到响应中，并在输出前调用响应钩子。
 // 参数：
 //   - c: Gin 上下文对象
 //   - status: HTTP 状态码
@@ -117,6 +195,13 @@ func write(c *gin.Context, status int, resp eresp.Response) {
 	if trace := getTraceID(c); trace != "" {
 		resp = resp.WithTrace(trace)
 	}
+	if responseHook != nil {
+		responseHook(c, &resp)
+	}
+	if field := options.TraceJSONField; resp.TraceID != "" && field != defaultTraceJSONField {
+		c.JSON(status, tracedResponse{Response: resp, field: field})
+		return
+	}
 	c.JSON(status, resp)
 }
 
@@ -135,7 +220,19 @@ func resolveStatus(err error) int {
 	return http.StatusInternalServerError
 }
 
-// getTraceID 内部函数：从 Gin Context 中获取 trace ID。
+// wantsJSON 内部函数：判断客户端是否期望 JSON 响应。
+// 参数：
+//   - c: Gin 上下文对象
+//
+// 返回值：XHR 请求或 Accept 包含 application/json 时返回 true
+func wantsJSON(c *gin.Context) bool {
+	if strings.EqualFold(c.GetHeader("X-Requested-With"), "XMLHttpRequest") {
+		return true
+	}
+	return strings.Contains(c.GetHeader("Accept"), "application/json")
+}
+
+// getTraceID 内部函数：从 Gin Context 中获取 trace ID，键名由 Configure 设置，默认为 TraceIDKey。
 // 参数：
 //   - c: Gin 上下文对象
 //
@@ -145,7 +242,7 @@ func getTraceID(c *gin.Context) string {
 		return ""
 	}
 
-	v, ok := c.Get(TraceIDKey)
+	v, ok := c.Get(options.TraceContextKey)
 	if !ok {
 		return ""
 	}
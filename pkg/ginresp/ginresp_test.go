@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/qq1060656096/bizutil/eresp"
 	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/bizutil/qsql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -675,3 +676,207 @@ func BenchmarkErr(b *testing.B) {
 		w.Body.Reset()
 	}
 }
+
+func TestRedirect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		status   int
+		expected int
+		json     bool
+	}{
+		{
+			name:     "browser redirect",
+			headers:  map[string]string{"Accept": "text/html,application/xhtml+xml"},
+			status:   http.StatusFound,
+			expected: http.StatusFound,
+		},
+		{
+			name:     "browser see other",
+			status:   http.StatusSeeOther,
+			expected: http.StatusSeeOther,
+		},
+		{
+			name:     "xhr request",
+			headers:  map[string]string{"X-Requested-With": "XMLHttpRequest"},
+			status:   http.StatusFound,
+			expected: http.StatusOK,
+			json:     true,
+		},
+		{
+			name:     "json accept",
+			headers:  map[string]string{"Accept": "application/json"},
+			status:   http.StatusSeeOther,
+			expected: http.StatusOK,
+			json:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				c.Request.Header.Set(k, v)
+			}
+			c.Set(TraceIDKey, "trace-redirect")
+
+			Redirect(c, tt.status, "/login?from=home")
+
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Equal(t, "/login?from=home", w.Header().Get("Location"))
+			if tt.json {
+				assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+				assert.Contains(t, w.Body.String(), `"code":0`)
+				assert.Contains(t, w.Body.String(), `"location":"/login?from=home"`)
+				assert.Contains(t, w.Body.String(), `"trace_id":"trace-redirect"`)
+			} else {
+				assert.NotContains(t, w.Body.String(), `"code":0`)
+			}
+		})
+	}
+}
+
+func TestFailStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		httpStatus int
+		code       int
+		msg        string
+		details    any
+	}{
+		{
+			name:       "bad request",
+			httpStatus: http.StatusBadRequest,
+			code:       1001,
+			msg:        "invalid param",
+		},
+		{
+			name:       "conflict with details",
+			httpStatus: http.StatusConflict,
+			code:       2001,
+			msg:        "already exists",
+			details:    map[string]any{"field": "name"},
+		},
+		{
+			name:       "status independent of code",
+			httpStatus: http.StatusUnprocessableEntity,
+			code:       1014000001,
+			msg:        "validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			FailStatus(c, tt.httpStatus, tt.code, tt.msg, tt.details)
+
+			assert.Equal(t, tt.httpStatus, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":`+fmt.Sprintf("%d", tt.code))
+			assert.Contains(t, w.Body.String(), `"message":"`+tt.msg+`"`)
+			if tt.details != nil {
+				assert.Contains(t, w.Body.String(), `"field":"name"`)
+			}
+		})
+	}
+}
+
+func TestOKList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(TraceIDKey, "trace-list")
+
+	OKList(c, []map[string]any{{"id": 1}, {"id": 2}}, 10)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":0`)
+	assert.Contains(t, w.Body.String(), `"data":{"list":[{"id":1},{"id":2}],"total":10}`)
+	assert.Contains(t, w.Body.String(), `"trace_id":"trace-list"`)
+}
+
+func TestOKPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(TraceIDKey, "trace-page")
+
+	OKPage(c, []map[string]any{{"id": 1}, {"id": 2}}, 25, 2, 10)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"code": 0,
+		"message": "OK",
+		"data": {"list": [{"id": 1}, {"id": 2}], "total": 25, "page": 2, "size": 10},
+		"trace_id": "trace-page"
+	}`, w.Body.String())
+}
+
+func TestOKPage_EmptyList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	OKPage(c, []string{}, 0, 1, 20)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":{"list":[],"page":1,"size":20,"total":0}`)
+	assert.NotContains(t, w.Body.String(), `"trace_id"`)
+}
+
+func TestCreated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(TraceIDKey, "trace-created")
+
+	Created(c, map[string]any{"id": 1})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"code":0,"message":"OK","data":{"id":1},"trace_id":"trace-created"}`, w.Body.String())
+}
+
+func TestNoContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.DELETE("/users/:id", func(c *gin.Context) {
+		c.Set(TraceIDKey, "trace-delete")
+		NoContent(c)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/users/1", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestFailValidators(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	FailValidators(c, []*qsql.ValidatorError{
+		qsql.NewValidatorError(qsql.ErrValidatorRequired, "name", "name_required", "name is required"),
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":1014000001`)
+	assert.Contains(t, w.Body.String(), `"message":"validation failed"`)
+	assert.Contains(t, w.Body.String(), `"field":"name"`)
+	assert.Contains(t, w.Body.String(), `"code":"name_required"`)
+}
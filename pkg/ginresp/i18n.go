@@ -0,0 +1,52 @@
+package ginresp
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/eresp"
+)
+
+// Translator 根据请求语言翻译消息，找不到翻译时应返回 key。
+type Translator func(c *gin.Context, key string, data map[string]any) string
+
+// translator 全局翻译函数，未设置时直接返回 key。
+var translator Translator
+
+// SetTranslator 设置 FailT、ErrT 使用的翻译函数，nil 表示不翻译。
+// 应在启动时设置一次，运行期间修改不保证并发安全。
+//
+// 通常使用 i18n 服务按请求语言翻译：
+//
+//	ginresp.SetTranslator(svc.MustTCtx)
+func SetTranslator(t Translator) {
+	translator = t
+}
+
+// translate 内部函数：翻译消息，未设置翻译函数时返回 key。
+func translate(c *gin.Context, key string, data map[string]any) string {
+	if translator == nil || key == "" {
+		return key
+	}
+	return translator(c, key, data)
+}
+
+// FailT 返回业务错误，message 由 msgKey 按请求语言翻译，找不到翻译时使用 msgKey。
+// 参数：
+//   - c: Gin 上下文对象
+//   - code: 业务错误码，用于前端判断具体错误类型
+//   - msgKey: 翻译键名
+//   - data: 模板数据，用于替换翻译文本中的占位符
+func FailT(c *gin.Context, code int, msgKey string, data map[string]any) {
+	Fail(c, code, translate(c, msgKey, data), nil)
+}
+
+// ErrT 根据 error 自动生成响应，并将错误消息作为翻译键按请求语言翻译。
+// 参数：
+//   - c: Gin 上下文对象
+//   - err: 错误对象，errcode.Error 的 message 作为翻译键
+//   - data: 模板数据，用于替换翻译文本中的占位符
+func ErrT(c *gin.Context, err error, data map[string]any) {
+	status := resolveStatus(err)
+	resp := eresp.FromError(err, nil)
+	resp.Message = translate(c, resp.Message, data)
+	write(c, status, resp)
+}
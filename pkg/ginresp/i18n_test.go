@@ -0,0 +1,107 @@
+package ginresp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/mi18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTranslator 使用临时翻译文件设置翻译函数，语言从请求 context 读取
+func setupTranslator(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en.yaml"), []byte(
+		"user.not_found: \"user {{.ID}} not found\"\nerr.forbidden: \"forbidden\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "zh.yaml"), []byte(
+		"user.not_found: \"用户 {{.ID}} 不存在\"\nerr.forbidden: \"无权访问\"\n"), 0o644))
+
+	i18n := mi18n.New(dir, "en")
+	SetTranslator(func(c *gin.Context, key string, data map[string]any) string {
+		return i18n.TCtx(c.Request.Context(), key, data)
+	})
+	t.Cleanup(func() { SetTranslator(nil) })
+}
+
+func newLangContext(lang string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if lang != "" {
+		req = req.WithContext(mi18n.WithLang(req.Context(), lang))
+	}
+	c.Request = req
+	return c, w
+}
+
+func TestFailT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupTranslator(t)
+
+	tests := []struct {
+		name    string
+		lang    string
+		key     string
+		wantMsg string
+	}{
+		{name: "中文翻译", lang: "zh", key: "user.not_found", wantMsg: `"message":"用户 1001 不存在"`},
+		{name: "英文翻译", lang: "en", key: "user.not_found", wantMsg: `"message":"user 1001 not found"`},
+		{name: "默认语言", lang: "", key: "user.not_found", wantMsg: `"message":"user 1001 not found"`},
+		{name: "缺失翻译回退为key", lang: "zh", key: "user.missing", wantMsg: `"message":"user.missing"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newLangContext(tt.lang)
+			c.Set(TraceIDKey, "trace-t")
+
+			FailT(c, 1014040001, tt.key, map[string]any{"ID": 1001})
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":1014040001`)
+			assert.Contains(t, w.Body.String(), tt.wantMsg)
+			assert.Contains(t, w.Body.String(), `"trace_id":"trace-t"`)
+		})
+	}
+}
+
+func TestErrT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupTranslator(t)
+
+	t.Run("errcode 消息翻译", func(t *testing.T) {
+		c, w := newLangContext("zh")
+		ErrT(c, errcode.New(1014030001, "err.forbidden"), nil)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1014030001`)
+		assert.Contains(t, w.Body.String(), `"message":"无权访问"`)
+	})
+
+	t.Run("缺失翻译回退为key", func(t *testing.T) {
+		c, w := newLangContext("zh")
+		ErrT(c, errors.New("db down"), nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"message":"internal server error"`)
+	})
+}
+
+func TestFailT_WithoutTranslator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetTranslator(nil)
+
+	c, w := newLangContext("zh")
+	FailT(c, 1001, "user.not_found", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"user.not_found"`)
+}
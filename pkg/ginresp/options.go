@@ -0,0 +1,94 @@
+package ginresp
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/eresp"
+)
+
+// defaultTraceJSONField 响应中 trace ID 的默认字段名，与 eresp.Response 保持一致
+const defaultTraceJSONField = "trace_id"
+
+// Options 包级配置。
+type Options struct {
+	// TraceContextKey Gin Context 中 trace ID 的键名，默认 TraceIDKey
+	TraceContextKey string
+	// TraceJSONField 响应中 trace ID 的字段名，默认 trace_id
+	TraceJSONField string
+}
+
+// options 当前生效的配置
+var options = Options{
+	TraceContextKey: TraceIDKey,
+	TraceJSONField:  defaultTraceJSONField,
+}
+
+// Configure 修改包级配置，空字段使用默认值。
+// 应在启动时调用一次，运行期间修改不保证并发安全。
+//
+// 示例：
+//
+//	ginresp.Configure(ginresp.Options{TraceContextKey: "request_id", TraceJSONField: "request_id"})
+func Configure(opts Options) {
+	if opts.TraceContextKey == "" {
+		opts.TraceContextKey = TraceIDKey
+	}
+	if opts.TraceJSONField == "" {
+		opts.TraceJSONField = defaultTraceJSONField
+	}
+	options = opts
+}
+
+// ResponseHook 响应钩子，可在输出前修改响应。
+type ResponseHook func(c *gin.Context, resp *eresp.Response)
+
+// responseHook 全局响应钩子
+var responseHook ResponseHook
+
+// SetResponseHook 设置响应钩子，所有响应在写入前都会调用，nil 表示不使用钩子。
+// 应在启动时设置一次，运行期间修改不保证并发安全，也不支持按请求设置。
+//
+// 示例：
+//
+//	ginresp.SetResponseHook(func(c *gin.Context, resp *eresp.Response) {
+//		if resp.Code != eresp.OkCode {
+//			resp.Details = gin.H{"version": buildVersion, "details": resp.Details}
+//		}
+//	})
+func SetResponseHook(hook ResponseHook) {
+	responseHook = hook
+}
+
+// tracedResponse 使用自定义字段名输出 trace ID 的响应
+type tracedResponse struct {
+	eresp.Response
+	field string
+}
+
+// MarshalJSON 在 eresp.Response 的 JSON 末尾追加 trace ID 字段
+func (r tracedResponse) MarshalJSON() ([]byte, error) {
+	resp := r.Response
+	resp.TraceID = ""
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	field, err := json.Marshal(r.field)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(r.Response.TraceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// body 至少包含 code、message 字段，以 } 结尾
+	out := make([]byte, 0, len(body)+len(field)+len(value)+2)
+	out = append(out, body[:len(body)-1]...)
+	out = append(out, ',')
+	out = append(out, field...)
+	out = append(out, ':')
+	out = append(out, value...)
+	return append(out, '}'), nil
+}
@@ -0,0 +1,108 @@
+package ginresp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/eresp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure_TraceField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(Options{TraceContextKey: "request_id", TraceJSONField: "request_id"})
+	t.Cleanup(func() { Configure(Options{}) })
+
+	t.Run("OK", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("request_id", "req-123")
+		// 默认键名不再生效
+		c.Set(TraceIDKey, "ignored")
+
+		OK(c, gin.H{"id": 1})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"code":0,"message":"OK","data":{"id":1},"request_id":"req-123"}`, w.Body.String())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("request_id", "req-456")
+
+		Fail(c, 1014000001, "invalid param", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"code":1014000001,"message":"invalid param","request_id":"req-456"}`, w.Body.String())
+		assert.NotContains(t, w.Body.String(), `"trace_id"`)
+	})
+
+	t.Run("无trace id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		OK(c, nil)
+
+		assert.JSONEq(t, `{"code":0,"message":"OK"}`, w.Body.String())
+	})
+}
+
+func TestConfigure_Defaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(Options{TraceJSONField: "request_id"})
+	Configure(Options{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(TraceIDKey, "trace-default")
+
+	OK(c, nil)
+
+	assert.Contains(t, w.Body.String(), `"trace_id":"trace-default"`)
+}
+
+func TestSetResponseHook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// 钩子补充缺失的 trace id，并为错误响应附加版本信息
+	SetResponseHook(func(c *gin.Context, resp *eresp.Response) {
+		if resp.TraceID == "" {
+			resp.TraceID = c.GetHeader("X-Request-ID")
+		}
+		if resp.Code != eresp.OkCode {
+			resp.Details = gin.H{"version": "v1.2.3"}
+		}
+	})
+	t.Cleanup(func() { SetResponseHook(nil) })
+
+	newContext := func() (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("X-Request-ID", "hook-trace")
+		return c, w
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		c, w := newContext()
+		OK(c, gin.H{"id": 1})
+		assert.JSONEq(t, `{"code":0,"message":"OK","data":{"id":1},"trace_id":"hook-trace"}`, w.Body.String())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		c, w := newContext()
+		Fail(c, 1001, "invalid param", nil)
+		assert.JSONEq(t, `{"code":1001,"message":"invalid param","details":{"version":"v1.2.3"},"trace_id":"hook-trace"}`, w.Body.String())
+	})
+
+	t.Run("自定义trace字段", func(t *testing.T) {
+		Configure(Options{TraceJSONField: "request_id"})
+		defer Configure(Options{})
+
+		c, w := newContext()
+		OK(c, nil)
+		assert.JSONEq(t, `{"code":0,"message":"OK","request_id":"hook-trace"}`, w.Body.String())
+	})
+}
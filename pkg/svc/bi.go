@@ -0,0 +1,76 @@
+package svc
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/bizutil/errcode"
+	"github.com/qq1060656096/drugo-provider/biapi/biz"
+	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
+	"gorm.io/gorm"
+)
+
+// BiRequestBuilder 根据当前请求构建 BI 模板执行请求。
+//
+// 通常在此完成查询参数绑定与校验，并组装 Params / Sys / Users。
+// 返回的 error 会以参数错误（HTTP 400）响应。
+type BiRequestBuilder func(c *gin.Context) (*biz.ExecuteRequest, error)
+
+// HandleBiList 执行 BI 列表模板并输出分页列表响应。
+//
+// 处理流程：
+//   - 调用 build 绑定请求并构建 ExecuteRequest
+//   - 构建模板，存在校验错误时响应 ginresp.FailValidators（不执行查询）
+//   - 通过 repo 执行构建结果（不重复构建模板，自动开启 WithCount 查询总数）
+//   - 执行失败时响应 ginresp.Err
+//   - 成功时响应 ginresp.OKPage，page、size 取自 ExecuteRequest 的 Page、PageSize
+//
+// 参数说明：
+//   - c：Gin 请求上下文
+//   - repo：BI 模板仓储
+//   - tplDB：模板所在数据库
+//   - execDB：执行 SQL 的数据库
+//   - build：请求构建函数
+//
+// 使用示例：
+//
+//	svc.HandleBiList(c, repo, tplDB, execDB, func(c *gin.Context) (*biz.ExecuteRequest, error) {
+//		var q ListQuery
+//		if err := c.ShouldBindQuery(&q); err != nil {
+//			return nil, err
+//		}
+//		return &biz.ExecuteRequest{PlatformId: 1, Code: "user_list", Env: biz.EnvProd, Params: q}, nil
+//	})
+func HandleBiList(c *gin.Context, repo biz.BiRepo, tplDB, execDB *gorm.DB, build BiRequestBuilder) {
+	req, err := build(c)
+	if err != nil {
+		ginresp.Err(c, errcode.Wrap(ginresp.CodeValidationFailed, err, "invalid request"), nil)
+		return
+	}
+	req.WithCount = true
+
+	// 先构建以便在执行查询前拦截模板校验错误
+	ctx := c.Request.Context()
+	buildResult, err := repo.Build(ctx, tplDB, req)
+	var validationErr *biz.ValidationError
+	if errors.As(err, &validationErr) {
+		ginresp.FailValidators(c, validationErr.Errors)
+		return
+	}
+	if err != nil {
+		ginresp.Err(c, err, nil)
+		return
+	}
+	if buildResult.SQLStmt.HasValidatorErrors() {
+		ginresp.FailValidators(c, buildResult.SQLStmt.ValidatorsErrors)
+		return
+	}
+
+	result, err := repo.ExecuteBuild(ctx, execDB, req, buildResult)
+	if err != nil {
+		ginresp.Err(c, err, nil)
+		return
+	}
+
+	ginresp.OKPage(c, result.Data, result.Count, req.Page, req.PageSize)
+}
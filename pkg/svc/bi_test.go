@@ -0,0 +1,219 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo-provider/biapi/biz"
+	"github.com/qq1060656096/drugo-provider/biapi/data"
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const testBiListTpl = `{vRequired . "page_size" "page_size_required" "page_size is required" "params.page_size"}` +
+	`SELECT id, name, age FROM users WHERE {expr . "age" ">=" "params.min_age"} ` +
+	`ORDER BY id LIMIT {val . "params.page_size"} OFFSET {val . "params.offset"}`
+
+// setupBiTestDB 初始化 drugo 应用与内存 sqlite 数据库（模板表 + 业务表）。
+func setupBiTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0o755))
+	drugo.SetApp(drugo.MustNewApp(drugo.WithRoot(root)))
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	stmts := []string{
+		`CREATE TABLE bi_template (
+			template_id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform_id INTEGER NOT NULL,
+			company_id INTEGER NOT NULL DEFAULT 0,
+			code VARCHAR(64) NOT NULL,
+			name VARCHAR(128) NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 1,
+			created_at DATETIME,
+			updated_at DATETIME,
+			deleted_at DATETIME DEFAULT NULL
+		)`,
+		`CREATE TABLE bi_template_data (
+			td_id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform_id INTEGER NOT NULL,
+			template_id INTEGER NOT NULL,
+			company_id INTEGER NOT NULL,
+			env VARCHAR(8) NOT NULL DEFAULT 'test',
+			op_type INTEGER NOT NULL,
+			content TEXT NOT NULL,
+			checksum CHAR(32) NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 1,
+			created_at DATETIME,
+			updated_at DATETIME,
+			deleted_at DATETIME DEFAULT NULL
+		)`,
+		`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(64) NOT NULL, age INTEGER NOT NULL)`,
+		`INSERT INTO users (name, age) VALUES ('u1', 18), ('u2', 20), ('u3', 25), ('u4', 30), ('u5', 35)`,
+		`INSERT INTO bi_template (template_id, platform_id, code) VALUES (1, 1, 'user_list')`,
+	}
+	for _, stmt := range stmts {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	require.NoError(t, db.Create(&data.TemplateData{
+		PlatformId: 1,
+		TemplateId: 1,
+		Env:        biz.EnvTest,
+		OpType:     biz.OpTypeList,
+		Content:    testBiListTpl,
+		Status:     1,
+	}).Error)
+	return db
+}
+
+// userListQuery 列表查询参数。
+type userListQuery struct {
+	MinAge   int `form:"min_age" json:"min_age"`
+	Page     int `form:"page" json:"-" binding:"required,min=1"`
+	PageSize int `form:"page_size" json:"page_size,omitempty"`
+	Offset   int `json:"offset"`
+}
+
+func userListBuilder(c *gin.Context) (*biz.ExecuteRequest, error) {
+	var q userListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		return nil, err
+	}
+	q.Offset = (q.Page - 1) * q.PageSize
+	return &biz.ExecuteRequest{
+		PlatformId: 1,
+		Code:       "user_list",
+		Env:        biz.EnvTest,
+		Params:     q,
+		Page:       q.Page,
+		PageSize:   q.PageSize,
+	}, nil
+}
+
+// countingBiRepo 统计 Build 与 Execute 的调用次数。
+type countingBiRepo struct {
+	biz.BiRepo
+	builds   int
+	executes int
+}
+
+func (r *countingBiRepo) Build(ctx context.Context, tplDb *gorm.DB, req *biz.ExecuteRequest) (*biz.BuildResult, error) {
+	r.builds++
+	return r.BiRepo.Build(ctx, tplDb, req)
+}
+
+func (r *countingBiRepo) Execute(ctx context.Context, tplDb, execDB *gorm.DB, req *biz.ExecuteRequest) (*biz.ExecuteResult, error) {
+	r.executes++
+	return r.BiRepo.Execute(ctx, tplDb, execDB, req)
+}
+
+func TestHandleBiList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupBiTestDB(t)
+	repo := &countingBiRepo{BiRepo: data.NewBiRepo()}
+
+	r := gin.New()
+	r.GET("/users", func(c *gin.Context) {
+		HandleBiList(c, repo, db, db, userListBuilder)
+	})
+
+	do := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users?"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("分页列表", func(t *testing.T) {
+		w := do("min_age=20&page=2&page_size=2")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Code int `json:"code"`
+			Data struct {
+				List  []map[string]any `json:"list"`
+				Total int64            `json:"total"`
+				Page  int              `json:"page"`
+				Size  int              `json:"size"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 0, resp.Code)
+		assert.Equal(t, int64(4), resp.Data.Total)
+		assert.Equal(t, 2, resp.Data.Page)
+		assert.Equal(t, 2, resp.Data.Size)
+		require.Len(t, resp.Data.List, 2)
+		assert.Equal(t, "u4", resp.Data.List[0]["name"])
+		assert.Equal(t, "u5", resp.Data.List[1]["name"])
+	})
+
+	t.Run("模板只构建一次", func(t *testing.T) {
+		repo.builds, repo.executes = 0, 0
+		require.Equal(t, http.StatusOK, do("min_age=20&page=1&page_size=2").Code)
+		assert.Equal(t, 1, repo.builds)
+		assert.Equal(t, 0, repo.executes)
+	})
+
+	t.Run("最后一页", func(t *testing.T) {
+		w := do("min_age=20&page=3&page_size=3")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"list":[]`)
+		assert.Contains(t, w.Body.String(), `"total":4`)
+	})
+
+	t.Run("模板校验错误", func(t *testing.T) {
+		w := do("min_age=20&page=1")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":`+strconv.Itoa(1014000001))
+		assert.Contains(t, w.Body.String(), `"page_size_required"`)
+	})
+
+	t.Run("请求绑定错误", func(t *testing.T) {
+		w := do("min_age=20&page=0&page_size=2")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":`+strconv.Itoa(1014000001))
+	})
+
+	t.Run("查询错误", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+		HandleBiList(c, repo, db, db, func(c *gin.Context) (*biz.ExecuteRequest, error) {
+			return &biz.ExecuteRequest{PlatformId: 1, Code: "not_exists", Env: biz.EnvTest}, nil
+		})
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"INTERNAL_ERROR"`)
+	})
+}
+
+func TestHandleBiList_StrictValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupBiTestDB(t)
+	repo := data.NewBiRepo(data.WithStrictValidation(true))
+
+	r := gin.New()
+	r.GET("/users", func(c *gin.Context) {
+		HandleBiList(c, repo, db, db, userListBuilder)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?min_age=20&page=1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"page_size_required"`)
+}
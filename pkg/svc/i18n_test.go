@@ -2,8 +2,13 @@ package svc
 
 import (
 	"testing"
+
+	"github.com/qq1060656096/drugo-provider/pkg/ginresp"
 )
 
+// 编译时检查，确保 MustTCtx 可直接作为 ginresp 的翻译函数：ginresp.SetTranslator(svc.MustTCtx)。
+var _ ginresp.Translator = MustTCtx
+
 func TestMustI18n(t *testing.T) {
 	// 这个测试需要完整的kernel环境，这里只做基本的结构测试
 	// 实际测试需要集成测试环境
@@ -0,0 +1,79 @@
+package redissvc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 编译时检查，确保 commandLogHook 实现了 redis.Hook 接口。
+var _ redis.Hook = (*commandLogHook)(nil)
+
+// commandLogHook 以 debug 级别记录每条 Redis 命令的名称、参数个数、耗时与错误。
+type commandLogHook struct {
+	logger *zap.Logger
+}
+
+// newCommandLogHook 创建命令日志 Hook。
+func newCommandLogHook(logger *zap.Logger) *commandLogHook {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &commandLogHook{logger: logger}
+}
+
+// DialHook 不做处理。
+func (h *commandLogHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 记录单条命令。
+func (h *commandLogHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		begin := time.Now()
+		err := next(ctx, cmd)
+		if ce := h.logger.Check(zap.DebugLevel, "redis command"); ce != nil {
+			ce.Write(
+				zap.String("cmd", cmd.FullName()),
+				zap.Int("args", len(cmd.Args())),
+				zap.Duration("duration", time.Since(begin)),
+				commandErrorField(err),
+			)
+		}
+		return err
+	}
+}
+
+// ProcessPipelineHook 记录 pipeline 及事务中的命令。
+func (h *commandLogHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		begin := time.Now()
+		err := next(ctx, cmds)
+		if ce := h.logger.Check(zap.DebugLevel, "redis pipeline"); ce != nil {
+			names := make([]string, 0, len(cmds))
+			for _, cmd := range cmds {
+				names = append(names, cmd.FullName())
+			}
+			ce.Write(
+				zap.Strings("cmds", names),
+				zap.Duration("duration", time.Since(begin)),
+				commandErrorField(err),
+			)
+		}
+		return err
+	}
+}
+
+// commandErrorField 返回错误字段，redis.Nil 表示键不存在，不视为错误。
+func commandErrorField(err error) zap.Field {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return zap.Skip()
+	}
+	return zap.Error(err)
+}
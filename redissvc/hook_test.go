@@ -0,0 +1,87 @@
+package redissvc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCommandLogHook(t *testing.T) {
+	mr := miniredis.RunT(t)
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	client.AddHook(newCommandLogHook(zap.New(core)))
+
+	ctx := context.Background()
+	require.NoError(t, client.Set(ctx, "k", "v", 0).Err())
+	assert.Equal(t, "v", client.Get(ctx, "k").Val())
+	assert.ErrorIs(t, client.Get(ctx, "missing").Err(), redis.Nil)
+
+	// 建立连接时的握手命令同样会被记录，这里只检查业务命令
+	assert.Equal(t, 1, logs.FilterField(zap.String("cmd", "set")).Len())
+	gets := logs.FilterField(zap.String("cmd", "get")).All()
+	require.Len(t, gets, 2)
+
+	get := gets[0]
+	assert.Equal(t, "redis command", get.Message)
+	assert.Equal(t, zapcore.DebugLevel, get.Level)
+	fields := get.ContextMap()
+	assert.EqualValues(t, 2, fields["args"])
+	assert.Contains(t, fields, "duration")
+	assert.NotContains(t, fields, "error")
+
+	// 键不存在不记录为错误
+	assert.NotContains(t, gets[1].ContextMap(), "error")
+
+	_, err := client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Incr(ctx, "n")
+		p.Incr(ctx, "n")
+		return nil
+	})
+	require.NoError(t, err)
+	pipeline := logs.FilterMessage("redis pipeline").All()
+	require.NotEmpty(t, pipeline)
+	assert.Equal(t, []interface{}{"incr", "incr"}, pipeline[len(pipeline)-1].ContextMap()["cmds"])
+}
+
+func TestRedisService_LogCommands(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+		"default": {
+			"addr":         mr.Addr(),
+			"ping_on_boot": false,
+			"log_commands": true,
+		},
+		"quiet": {
+			"addr":         mr.Addr(),
+			"ping_on_boot": false,
+		},
+	})
+
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+	t.Cleanup(func() { _ = service.Close(context.Background()) })
+
+	// 连接在首次获取时建立，替换日志以观察命令日志
+	core, logs := observer.New(zapcore.DebugLevel)
+	service.logger = zap.New(core)
+
+	service.MustClient("quiet").Get(ctx, "k")
+	assert.Zero(t, logs.FilterMessage("redis command").Len())
+
+	service.MustClient("default").Get(ctx, "k")
+	gets := logs.FilterField(zap.String("cmd", "get")).All()
+	require.Len(t, gets, 1)
+	fields := gets[0].ContextMap()
+	assert.Equal(t, "default", fields["name"])
+	assert.Contains(t, fields, "duration")
+}
@@ -0,0 +1,63 @@
+package redissvc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Subscribe 在指定实例上订阅 channels，等待服务端确认订阅后返回。
+// 返回的 *redis.PubSub 由调用方负责 Close。
+// 实例未注册时返回的错误可通过 errors.Is(err, mgredis.ErrClientNotFound) 判断。
+func (s *RedisService) Subscribe(ctx context.Context, instance string, channels ...string) (*redis.PubSub, error) {
+	client, err := s.Client(instance)
+	if err != nil {
+		return nil, err
+	}
+	pubsub := client.Subscribe(ctx, channels...)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("subscribe redis %s: %w", instance, err)
+	}
+	return pubsub, nil
+}
+
+// SubscribeFunc 订阅 channel 并在后台协程中对每条消息调用 handler，直到 ctx 取消。
+// 订阅确认后返回；ctx 取消时自动取消订阅并释放连接。
+//
+// 示例：
+//
+//	err := redisSvc.SubscribeFunc(ctx, "cache", "invalidate", func(msg string) {
+//		localCache.Delete(msg)
+//	})
+func (s *RedisService) SubscribeFunc(ctx context.Context, instance, channel string, handler func(msg string)) error {
+	pubsub, err := s.Subscribe(ctx, instance, channel)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer func() {
+			if err := pubsub.Close(); err != nil && s.logger != nil {
+				s.logger.Warn("failed to close redis subscription",
+					zap.String("name", instance), zap.String("channel", channel), zap.Error(err))
+			}
+		}()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				handler(msg.Payload)
+			}
+		}
+	}()
+	return nil
+}
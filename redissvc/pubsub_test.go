@@ -0,0 +1,71 @@
+package redissvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/qq1060656096/mgredis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisService_Subscribe(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+		"default": {
+			"addr": mr.Addr(),
+		},
+	})
+
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+	t.Cleanup(func() { _ = service.Close(context.Background()) })
+
+	t.Run("subscribe", func(t *testing.T) {
+		pubsub, err := service.Subscribe(ctx, "default", "events")
+		require.NoError(t, err)
+		defer pubsub.Close()
+
+		assert.Equal(t, 1, mr.Publish("events", "hello"))
+		msg, err := pubsub.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "events", msg.Channel)
+		assert.Equal(t, "hello", msg.Payload)
+	})
+
+	t.Run("unknown instance", func(t *testing.T) {
+		pubsub, err := service.Subscribe(ctx, "missing", "events")
+		assert.Nil(t, pubsub)
+		assert.ErrorIs(t, err, mgredis.ErrClientNotFound)
+
+		err = service.SubscribeFunc(ctx, "missing", "events", func(string) {})
+		assert.ErrorIs(t, err, mgredis.ErrClientNotFound)
+	})
+
+	t.Run("subscribe func", func(t *testing.T) {
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		received := make(chan string, 1)
+		require.NoError(t, service.SubscribeFunc(subCtx, "default", "invalidate", func(msg string) {
+			received <- msg
+		}))
+
+		assert.Equal(t, 1, mr.Publish("invalidate", "user:1"))
+		select {
+		case msg := <-received:
+			assert.Equal(t, "user:1", msg)
+		case <-time.After(5 * time.Second):
+			t.Fatal("未收到订阅消息")
+		}
+
+		// 取消 ctx 后退出循环并取消订阅
+		cancel()
+		assert.Eventually(t, func() bool {
+			return mr.PubSubNumSub("invalidate")["invalidate"] == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, 0, mr.Publish("invalidate", "user:2"))
+	})
+}
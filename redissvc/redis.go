@@ -3,12 +3,15 @@ package redissvc
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"sync"
 
+	"github.com/qq1060656096/bizutil/registry"
 	"github.com/qq1060656096/drugo/kernel"
 	"github.com/qq1060656096/mgredis"
+	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
@@ -24,6 +27,10 @@ type RedisService struct {
 	logger *zap.Logger
 
 	group mgredis.Group
+	// tlsConfigs 按实例名称（RedisConfig.Name）保存 TLS 配置，仅在 Boot 时写入
+	tlsConfigs map[string]*tls.Config
+	// logCommands 按实例名称记录是否开启命令日志（log_commands），仅在 Boot 时写入
+	logCommands map[string]bool
 
 	once    sync.Once
 	bootErr error
@@ -31,10 +38,13 @@ type RedisService struct {
 
 // New 创建 RedisService
 func New() *RedisService {
-	return &RedisService{
-		name:  Name,
-		group: mgredis.New(),
+	s := &RedisService{
+		name:        Name,
+		tlsConfigs:  make(map[string]*tls.Config),
+		logCommands: make(map[string]bool),
 	}
+	s.group = s.newGroup()
+	return s
 }
 
 func (s *RedisService) Name() string {
@@ -69,19 +79,55 @@ func (s *RedisService) boot(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("build redis config %s: %w", name, err)
 		}
+		if redisCfg.Name == "" {
+			redisCfg.Name = name
+		}
+
+		tlsCfg, err := s.buildTLSConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("build redis tls config %s: %w", name, err)
+		}
+		if tlsCfg != nil {
+			s.tlsConfigs[redisCfg.Name] = tlsCfg
+		}
+		if cfg.GetBool("log_commands") {
+			s.logCommands[redisCfg.Name] = true
+		}
 
 		s.logger.Info("register redis",
 			zap.String("name", name),
 			zap.String("addr", redisCfg.Addr),
 			zap.Int("db", redisCfg.DB),
+			zap.Bool("tls", tlsCfg != nil),
+			zap.Bool("log_commands", s.logCommands[redisCfg.Name]),
 		)
 
 		s.group.Register(ctx, name, redisCfg)
+
+		if cfg.IsSet("ping_on_boot") && !cfg.GetBool("ping_on_boot") {
+			continue
+		}
+		if err := s.ping(ctx, name); err != nil {
+			s.logger.Error("failed to ping redis", zap.String("name", name), zap.Error(err))
+			return err
+		}
 	}
 
 	return nil
 }
 
+// ping 建立指定实例的连接并执行 PING，受 ctx 的截止时间约束。
+func (s *RedisService) ping(ctx context.Context, name string) error {
+	client, err := s.group.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("ping redis %s: %w", name, err)
+	}
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("ping redis %s: %w", name, err)
+	}
+	return nil
+}
+
 // buildRedisConfig 构建 mgredis.RedisConfig
 func (s *RedisService) buildRedisConfig(v *viper.Viper) (mgredis.RedisConfig, error) {
 	cfg := mgredis.RedisConfig{
@@ -108,16 +154,52 @@ func (s *RedisService) Group() mgredis.Group {
 	return s.group
 }
 
-// Close 关闭所有 Redis 连接
+// Client 返回 Boot 时注册的 Redis 客户端，首次获取时建立连接。
+// 实例未注册时返回的错误可通过 errors.Is(err, mgredis.ErrClientNotFound) 判断。
+func (s *RedisService) Client(name string) (*redis.Client, error) {
+	client, err := s.group.Get(context.Background(), name)
+	if errors.Is(err, registry.ErrResourceNotFound) {
+		return nil, fmt.Errorf("%w: %s", mgredis.ErrClientNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get redis client %s: %w", name, err)
+	}
+	return client, nil
+}
+
+// MustClient 与 Client 相同，但获取失败时 panic。
+func (s *RedisService) MustClient(name string) *redis.Client {
+	client, err := s.Client(name)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Close 关闭所有 Redis 连接。
+// 单个实例关闭失败不会中断其余实例，所有错误以 errors.Join 合并返回。
 func (s *RedisService) Close(ctx context.Context) error {
 	if s.group == nil {
 		return nil
 	}
-	errs := s.group.Close(ctx)
+
+	var errs []error
+	for _, name := range s.group.List() {
+		if err := s.closeClient(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("close redis %s: %w", name, err))
+			if s.logger != nil {
+				s.logger.Error("failed to close redis", zap.String("name", name), zap.Error(err))
+			}
+		}
+	}
+
+	// 清空注册表，客户端均已关闭，重复关闭返回的 redis.ErrClosed 无需上报
+	_ = s.group.Close(ctx)
+
 	if len(errs) > 0 {
 		err := errors.Join(errs...)
 		if s.logger != nil {
-			s.logger.Error("redis service failed to close", zap.Error(err))
+			s.logger.Error("redis service failed to close", zap.Int("failed", len(errs)), zap.Error(err))
 		}
 		return err
 	}
@@ -126,3 +208,13 @@ func (s *RedisService) Close(ctx context.Context) error {
 	}
 	return nil
 }
+
+// closeClient 获取并关闭指定实例的客户端。
+// 不使用 group.Unregister，它会忽略 client.Close 返回的错误。
+func (s *RedisService) closeClient(ctx context.Context, name string) error {
+	client, err := s.group.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
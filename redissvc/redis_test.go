@@ -6,12 +6,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/qq1060656096/drugo/config"
 	"github.com/qq1060656096/drugo/kernel"
 	"github.com/qq1060656096/drugo/log"
+	"github.com/qq1060656096/mgredis"
+	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // mockKernel 模拟 kernel 接口
@@ -218,6 +223,9 @@ func TestRedisService_buildRedisConfig(t *testing.T) {
 
 // TestRedisService_Boot 测试 Boot 方法
 func TestRedisService_Boot(t *testing.T) {
+	addr := miniredis.RunT(t).Addr()
+	addr2 := miniredis.RunT(t).Addr()
+
 	tests := []struct {
 		name        string
 		configs     map[string]map[string]interface{}
@@ -227,7 +235,7 @@ func TestRedisService_Boot(t *testing.T) {
 			name: "单个redis实例",
 			configs: map[string]map[string]interface{}{
 				"main": {
-					"addr": "127.0.0.1:6379",
+					"addr": addr,
 					"db":   0,
 				},
 			},
@@ -237,15 +245,15 @@ func TestRedisService_Boot(t *testing.T) {
 			name: "多个redis实例",
 			configs: map[string]map[string]interface{}{
 				"main": {
-					"addr": "127.0.0.1:6379",
+					"addr": addr,
 					"db":   0,
 				},
 				"cache": {
-					"addr": "127.0.0.1:6379",
+					"addr": addr,
 					"db":   1,
 				},
 				"session": {
-					"addr": "127.0.0.1:6380",
+					"addr": addr2,
 					"db":   0,
 				},
 			},
@@ -255,7 +263,7 @@ func TestRedisService_Boot(t *testing.T) {
 			name: "包含无效实例",
 			configs: map[string]map[string]interface{}{
 				"main": {
-					"addr": "127.0.0.1:6379",
+					"addr": addr,
 					"db":   0,
 				},
 				"invalid": {
@@ -287,10 +295,11 @@ func TestRedisService_Boot(t *testing.T) {
 
 // TestRedisService_Boot_Once 测试 Boot 方法只执行一次
 func TestRedisService_Boot_Once(t *testing.T) {
+	addr := miniredis.RunT(t).Addr()
 	service := New()
 	configs := map[string]map[string]interface{}{
 		"main": {
-			"addr": "127.0.0.1:6379",
+			"addr": addr,
 			"db":   0,
 		},
 	}
@@ -333,10 +342,11 @@ func TestRedisService_Boot_Error_Persists(t *testing.T) {
 
 // TestRedisService_Boot_WithTimeout 测试带超时的启动
 func TestRedisService_Boot_WithTimeout(t *testing.T) {
+	addr := miniredis.RunT(t).Addr()
 	service := New()
 	configs := map[string]map[string]interface{}{
 		"main": {
-			"addr":          "127.0.0.1:6379",
+			"addr":          addr,
 			"db":            0,
 			"dial_timeout":  "5s",
 			"read_timeout":  "3s",
@@ -357,6 +367,8 @@ func TestRedisService_Boot_WithTimeout(t *testing.T) {
 
 // TestRedisService_Close 测试 Close 方法
 func TestRedisService_Close(t *testing.T) {
+	addr := miniredis.RunT(t).Addr()
+
 	tests := []struct {
 		name        string
 		setup       func(*testing.T) (*RedisService, context.Context)
@@ -368,7 +380,7 @@ func TestRedisService_Close(t *testing.T) {
 				service := New()
 				configs := map[string]map[string]interface{}{
 					"main": {
-						"addr": "127.0.0.1:6379",
+						"addr": addr,
 						"db":   0,
 					},
 				}
@@ -411,10 +423,11 @@ func TestRedisService_Integration(t *testing.T) {
 		t.Skip("跳过集成测试")
 	}
 
+	addr := miniredis.RunT(t).Addr()
 	service := New()
 	configs := map[string]map[string]interface{}{
 		"main": {
-			"addr":           "127.0.0.1:6379",
+			"addr":           addr,
 			"db":             0,
 			"pool_size":      10,
 			"min_idle_conns": 2,
@@ -423,7 +436,7 @@ func TestRedisService_Integration(t *testing.T) {
 			"write_timeout":  "3s",
 		},
 		"cache": {
-			"addr":           "127.0.0.1:6379",
+			"addr":           addr,
 			"db":             1,
 			"pool_size":      5,
 			"min_idle_conns": 1,
@@ -547,3 +560,119 @@ func BenchmarkRedisService_buildRedisConfig(b *testing.B) {
 		_, _ = service.buildRedisConfig(v)
 	}
 }
+
+// TestRedisService_Client 测试 Client / MustClient 方法
+func TestRedisService_Client(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+		"default": {
+			"addr": mr.Addr(),
+		},
+	})
+
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+	t.Cleanup(func() { _ = service.Close(context.Background()) })
+
+	t.Run("configured instance", func(t *testing.T) {
+		client, err := service.Client("default")
+		require.NoError(t, err)
+		require.NoError(t, client.Set(ctx, "k", "v", 0).Err())
+		v, err := mr.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "v", v)
+		assert.Same(t, client, service.MustClient("default"))
+	})
+
+	t.Run("unknown instance", func(t *testing.T) {
+		client, err := service.Client("missing")
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, mgredis.ErrClientNotFound)
+		assert.Contains(t, err.Error(), "missing")
+		assert.Panics(t, func() { service.MustClient("missing") })
+	})
+}
+
+// TestRedisService_Boot_Ping 测试启动时 PING 检查
+func TestRedisService_Boot_Ping(t *testing.T) {
+	// 监听后立即关闭，得到一个不可达的地址
+	mr := miniredis.RunT(t)
+	unreachable := mr.Addr()
+	mr.Close()
+
+	t.Run("unreachable instance", func(t *testing.T) {
+		service := New()
+		ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+			"orders": {
+				"addr":         unreachable,
+				"dial_timeout": "100ms",
+			},
+		})
+
+		err := service.Boot(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "orders")
+		assert.ErrorIs(t, err, mgredis.ErrPingFailed)
+	})
+
+	t.Run("ping_on_boot disabled", func(t *testing.T) {
+		service := New()
+		ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+			"orders": {
+				"addr":         unreachable,
+				"ping_on_boot": false,
+			},
+		})
+		assert.NoError(t, service.Boot(ctx))
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		service := New()
+		ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+			"orders": {
+				"addr": unreachable,
+			},
+		})
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := service.Boot(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "orders")
+	})
+}
+
+// TestRedisService_Close_Aggregate 测试关闭错误聚合
+func TestRedisService_Close_Aggregate(t *testing.T) {
+	addr := miniredis.RunT(t).Addr()
+	ctx := createTestContext(t, "redis", map[string]map[string]interface{}{
+		"main":    {"addr": addr},
+		"cache":   {"addr": addr},
+		"session": {"addr": addr},
+	})
+	service := New()
+	require.NoError(t, service.Boot(ctx))
+	core, logs := observer.New(zap.InfoLevel)
+	service.logger = zap.New(core)
+
+	// 提前关闭的客户端再次关闭时返回 redis.ErrClosed
+	for _, name := range []string{"main", "session"} {
+		require.NoError(t, service.MustClient(name).Close())
+	}
+
+	err := service.Close(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, redis.ErrClosed)
+	assert.Contains(t, err.Error(), "close redis main: "+redis.ErrClosed.Error())
+	assert.Contains(t, err.Error(), "close redis session: "+redis.ErrClosed.Error())
+	assert.NotContains(t, err.Error(), "cache")
+	assert.Empty(t, service.group.List())
+
+	failed := logs.FilterMessage("failed to close redis").All()
+	require.Len(t, failed, 2)
+	var names []string
+	for _, entry := range failed {
+		names = append(names, entry.ContextMap()["name"].(string))
+	}
+	assert.ElementsMatch(t, []string{"main", "session"}, names)
+}
@@ -0,0 +1,116 @@
+package redissvc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/qq1060656096/bizutil/registry"
+	"github.com/qq1060656096/mgredis"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidTLSConfig TLS 配置无效时返回此错误。
+var ErrInvalidTLSConfig = errors.New("redissvc: invalid tls config")
+
+// pingTimeout 打开连接时 PING 的超时时间，与 mgredis 保持一致。
+const pingTimeout = 5 * time.Second
+
+// newGroup 创建使用自定义 opener 的 mgredis.Group。
+//
+// mgredis 的 opener 不支持 TLS，这里按相同逻辑创建客户端，
+// 并根据实例名称附加 Boot 时解析的 tls.Config。
+func (s *RedisService) newGroup() mgredis.Group {
+	return registry.New[mgredis.RedisConfig, *redis.Client](s.openClient, closeClient)
+}
+
+// openClient 根据配置创建 Redis 客户端并执行 PING。
+func (s *RedisService) openClient(ctx context.Context, cfg mgredis.RedisConfig) (*redis.Client, error) {
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:            cfg.Addr,
+		Password:        cfg.Password,
+		DB:              cfg.DB,
+		PoolSize:        cfg.PoolSize,
+		MinIdleConns:    cfg.MinIdleConns,
+		DialTimeout:     cfg.DialTimeout,
+		ReadTimeout:     cfg.ReadTimeout,
+		WriteTimeout:    cfg.WriteTimeout,
+		MaxRetries:      cfg.MaxRetries,
+		PoolTimeout:     cfg.PoolTimeout,
+		ConnMaxIdleTime: cfg.IdleTimeout,
+		TLSConfig:       s.tlsConfigs[cfg.Name],
+	})
+	if s.logCommands[cfg.Name] {
+		client.AddHook(newCommandLogHook(s.logger.With(zap.String("name", cfg.Name))))
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("%w: %v", mgredis.ErrPingFailed, err)
+	}
+	return client, nil
+}
+
+// closeClient 关闭 Redis 客户端。
+func closeClient(ctx context.Context, client *redis.Client) error {
+	if client == nil {
+		return nil
+	}
+	return client.Close()
+}
+
+// buildTLSConfig 根据配置创建 tls.Config，未启用 TLS 时返回 nil。
+//
+// 支持的配置项：
+//   - tls_enabled：是否启用 TLS，默认 false
+//   - tls_insecure_skip_verify：是否跳过服务端证书校验
+//   - tls_ca_file：CA 证书文件（PEM），为空时使用系统根证书
+//   - tls_cert_file / tls_key_file：客户端证书与私钥（双向认证），需同时配置
+func (s *RedisService) buildTLSConfig(v *viper.Viper) (*tls.Config, error) {
+	if !v.GetBool("tls_enabled") {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: v.GetBool("tls_insecure_skip_verify"),
+	}
+
+	if caFile := v.GetString("tls_ca_file"); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read ca file: %v", ErrInvalidTLSConfig, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates found in %s", ErrInvalidTLSConfig, caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	certFile, keyFile := v.GetString("tls_cert_file"), v.GetString("tls_key_file")
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("%w: tls_cert_file and tls_key_file must be set together", ErrInvalidTLSConfig)
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: load key pair: %v", ErrInvalidTLSConfig, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
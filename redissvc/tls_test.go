@@ -0,0 +1,219 @@
+package redissvc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCerts 测试用的 CA 与服务端证书文件
+type testCerts struct {
+	caFile   string
+	certFile string
+	keyFile  string
+	caPool   *x509.CertPool
+	server   tls.Certificate
+}
+
+// newTestCerts 生成自签名 CA 及其签发的 127.0.0.1 证书，写入临时目录
+func newTestCerts(t *testing.T) *testCerts {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redissvc test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTpl, caTpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, caCert, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certs := &testCerts{
+		caFile:   filepath.Join(dir, "ca.pem"),
+		certFile: filepath.Join(dir, "cert.pem"),
+		keyFile:  filepath.Join(dir, "key.pem"),
+		caPool:   x509.NewCertPool(),
+	}
+	certs.caPool.AddCert(caCert)
+	writePEM(t, certs.caFile, "CERTIFICATE", caDER)
+	writePEM(t, certs.certFile, "CERTIFICATE", der)
+	writePEM(t, certs.keyFile, "EC PRIVATE KEY", keyDER)
+
+	certs.server, err = tls.LoadX509KeyPair(certs.certFile, certs.keyFile)
+	require.NoError(t, err)
+	return certs
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+}
+
+// TestRedisService_buildTLSConfig 测试 TLS 配置解析
+func TestRedisService_buildTLSConfig(t *testing.T) {
+	certs := newTestCerts(t)
+	service := New()
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		expectError bool
+		check       func(*testing.T, *tls.Config)
+	}{
+		{
+			name:   "默认不启用",
+			config: map[string]interface{}{"addr": "127.0.0.1:6379"},
+			check: func(t *testing.T, cfg *tls.Config) {
+				assert.Nil(t, cfg)
+			},
+		},
+		{
+			name: "启用并加载CA",
+			config: map[string]interface{}{
+				"tls_enabled": true,
+				"tls_ca_file": certs.caFile,
+			},
+			check: func(t *testing.T, cfg *tls.Config) {
+				require.NotNil(t, cfg)
+				require.NotNil(t, cfg.RootCAs)
+				assert.True(t, cfg.RootCAs.Equal(certs.caPool))
+				assert.False(t, cfg.InsecureSkipVerify)
+				assert.Empty(t, cfg.Certificates)
+			},
+		},
+		{
+			name: "跳过校验与客户端证书",
+			config: map[string]interface{}{
+				"tls_enabled":              true,
+				"tls_insecure_skip_verify": true,
+				"tls_cert_file":            certs.certFile,
+				"tls_key_file":             certs.keyFile,
+			},
+			check: func(t *testing.T, cfg *tls.Config) {
+				require.NotNil(t, cfg)
+				assert.True(t, cfg.InsecureSkipVerify)
+				assert.Nil(t, cfg.RootCAs)
+				assert.Len(t, cfg.Certificates, 1)
+			},
+		},
+		{
+			name: "CA文件不存在",
+			config: map[string]interface{}{
+				"tls_enabled": true,
+				"tls_ca_file": filepath.Join(t.TempDir(), "missing.pem"),
+			},
+			expectError: true,
+		},
+		{
+			name: "CA文件内容无效",
+			config: map[string]interface{}{
+				"tls_enabled": true,
+				"tls_ca_file": certs.keyFile,
+			},
+			expectError: true,
+		},
+		{
+			name: "只配置了证书没有私钥",
+			config: map[string]interface{}{
+				"tls_enabled":   true,
+				"tls_cert_file": certs.certFile,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			for key, value := range tt.config {
+				v.Set(key, value)
+			}
+
+			cfg, err := service.buildTLSConfig(v)
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrInvalidTLSConfig)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+// TestRedisService_Boot_TLS 测试通过 TLS 连接 Redis
+func TestRedisService_Boot_TLS(t *testing.T) {
+	certs := newTestCerts(t)
+	mr, err := miniredis.RunTLS(&tls.Config{Certificates: []tls.Certificate{certs.server}})
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	t.Run("CA校验通过", func(t *testing.T) {
+		service := New()
+		ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+			"secure": {
+				"addr":        mr.Addr(),
+				"tls_enabled": true,
+				"tls_ca_file": certs.caFile,
+			},
+		})
+		require.NoError(t, service.Boot(ctx))
+		t.Cleanup(func() { _ = service.Close(context.Background()) })
+
+		client := service.MustClient("secure")
+		require.NotNil(t, client.Options().TLSConfig)
+		assert.NoError(t, client.Set(ctx, "k", "v", 0).Err())
+	})
+
+	t.Run("未启用TLS连接失败", func(t *testing.T) {
+		service := New()
+		ctx := createTestContext(t, Name, map[string]map[string]interface{}{
+			"secure": {
+				"addr":         mr.Addr(),
+				"read_timeout": "200ms",
+			},
+		})
+		err := service.Boot(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "secure")
+	})
+}